@@ -7,6 +7,7 @@ package bloomfilter
 import (
 	"encoding/binary"
 	"math"
+	"math/bits"
 	"math/rand"
 
 	"github.com/zeebo/errs"
@@ -115,6 +116,60 @@ func (filter *Filter) Contains(pieceID storj.PieceID) bool {
 	return true
 }
 
+// AddIfAbsent adds pieceID to the bloom filter and returns true if it was not
+// already contained, as reported by Contains. The hashes are computed only
+// once. The filter must be synchronized externally like for Add.
+func (filter *Filter) AddIfAbsent(pieceID storj.PieceID) (added bool) {
+	offset, rangeOffset := initialConditions(filter.seed)
+
+	for k := byte(0); k < filter.hashCount; k++ {
+		hash, bit := subrange(offset, pieceID)
+
+		offset += rangeOffset
+		if offset >= len(storj.PieceID{}) {
+			offset -= len(storj.PieceID{})
+		}
+
+		bucket := hash % uint64(len(filter.table))
+		mask := byte(1 << (bit % 8))
+		if filter.table[bucket]&mask == 0 {
+			filter.table[bucket] |= mask
+			added = true
+		}
+	}
+
+	return added
+}
+
+// Clear removes all elements from the filter while keeping the size, hash
+// count and seed, so the filter can be reused.
+//
+// Note: when the filter was created with NewFromBytes the referenced data is zeroed.
+func (filter *Filter) Clear() {
+	for i := range filter.table {
+		filter.table[i] = 0
+	}
+}
+
+// BitCount returns the number of bits set in the filter.
+func (filter *Filter) BitCount() int {
+	count := 0
+	for _, b := range filter.table {
+		count += bits.OnesCount8(b)
+	}
+	return count
+}
+
+// FillRatio returns the fraction of the bits in the filter that are set. As it
+// approaches 1 the false positive rate grows, so it can be used to monitor
+// saturation.
+func (filter *Filter) FillRatio() float64 {
+	if len(filter.table) == 0 {
+		return 0
+	}
+	return float64(filter.BitCount()) / float64(8*len(filter.table))
+}
+
 func initialConditions(seed byte) (initialOffset, rangeOffset int) {
 	initialOffset = int(seed % 32)
 	rangeOffset = int(rangeOffsets[int(seed/32)%len(rangeOffsets)])
@@ -5,6 +5,7 @@ package bloomfilter_test
 
 import (
 	"flag"
+	"math"
 	"sort"
 	"testing"
 
@@ -32,6 +33,73 @@ func TestNoFalsePositive(t *testing.T) {
 	}
 }
 
+func TestBitCountAndFillRatio(t *testing.T) {
+	const numberOfPieces = 1000
+
+	filter := bloomfilter.NewOptimal(numberOfPieces, 0.1)
+	hashCount, size := filter.Parameters()
+	totalBits := 8 * size
+
+	require.Equal(t, 0, filter.BitCount())
+	require.Equal(t, 0.0, filter.FillRatio())
+
+	for _, pieceID := range generateTestIDs(numberOfPieces) {
+		filter.Add(pieceID)
+	}
+
+	// every add sets at most hashCount bits, and collisions are rare enough
+	// that the count stays close to the expected value.
+	count := filter.BitCount()
+	expected := float64(totalBits) * (1 - math.Exp(-float64(hashCount*numberOfPieces)/float64(totalBits)))
+	require.True(t, count <= hashCount*numberOfPieces, count)
+	require.InEpsilon(t, expected, float64(count), 0.1)
+
+	require.InDelta(t, float64(count)/float64(totalBits), filter.FillRatio(), 1e-9)
+
+	filter.Clear()
+	require.Equal(t, 0, filter.BitCount())
+}
+
+func TestAddIfAbsent(t *testing.T) {
+	const numberOfPieces = 1000
+
+	filter := bloomfilter.NewOptimal(numberOfPieces, 0.1)
+
+	for _, pieceID := range generateTestIDs(numberOfPieces) {
+		wasContained := filter.Contains(pieceID)
+		require.Equal(t, !wasContained, filter.AddIfAbsent(pieceID))
+		require.True(t, filter.Contains(pieceID))
+		require.False(t, filter.AddIfAbsent(pieceID))
+	}
+}
+
+func TestClear(t *testing.T) {
+	filter := bloomfilter.NewOptimal(1000, 0.1)
+	hashCount, size := filter.Parameters()
+	before := filter.Bytes()
+
+	pieceIDs := generateTestIDs(1000)
+	for _, pieceID := range pieceIDs {
+		filter.Add(pieceID)
+	}
+	require.True(t, filter.Contains(pieceIDs[0]))
+
+	filter.Clear()
+	require.False(t, filter.Contains(pieceIDs[0]))
+	require.Equal(t, before, filter.Bytes())
+
+	clearedHashCount, clearedSize := filter.Parameters()
+	require.Equal(t, hashCount, clearedHashCount)
+	require.Equal(t, size, clearedSize)
+
+	for _, pieceID := range pieceIDs {
+		filter.Add(pieceID)
+	}
+	for _, pieceID := range pieceIDs {
+		require.True(t, filter.Contains(pieceID))
+	}
+}
+
 func TestBytes(t *testing.T) {
 	for _, count := range []int{0, 100, 1000, 10000} {
 		filter := bloomfilter.NewOptimal(count, 0.1)
@@ -125,7 +125,7 @@ func (s *aesgcmDecrypter) Transform(out, in []byte, blockNum int64) ([]byte, err
 
 	plainData, err := s.aesgcm.Open(out, nonce[:], in, nil)
 	if err != nil {
-		return nil, ErrDecryptFailed.Wrap(err)
+		return nil, ErrDecryptFailed.Wrap(ErrDecryption)
 	}
 	return plainData, nil
 }
@@ -159,7 +159,7 @@ func DecryptAESGCM(cipherData []byte, key *storj.Key, nonce *AESGCMNonce) (data
 	}
 	plainData, err := aesgcm.Open(nil, nonce[:], cipherData, nil)
 	if err != nil {
-		return []byte{}, ErrDecryptFailed.Wrap(err)
+		return []byte{}, ErrDecryptFailed.Wrap(ErrDecryption)
 	}
 	return plainData, nil
 }
@@ -5,6 +5,8 @@
 package encryption
 
 import (
+	"errors"
+
 	"github.com/zeebo/errs"
 )
 
@@ -16,3 +18,22 @@ var ErrDecryptFailed = errs.Class("decryption failed, check encryption key")
 
 // ErrInvalidConfig is the errs class for invalid configuration.
 var ErrInvalidConfig = errs.Class("invalid encryption configuration")
+
+var (
+	// ErrTooManyEncryptedParts is returned when an encrypted path has more components
+	// than the unencrypted path it is added for.
+	ErrTooManyEncryptedParts = errors.New("encrypted path has more components than unencrypted path")
+	// ErrTooManyUnencryptedParts is returned when an unencrypted path has more components
+	// than the encrypted path it is added for.
+	ErrTooManyUnencryptedParts = errors.New("unencrypted path has more components than encrypted path")
+	// ErrPathMismatch is returned when a path component conflicts with an existing mapping.
+	ErrPathMismatch = errors.New("conflicting encrypted parts for unencrypted path")
+	// ErrDecryption is returned, wrapped in ErrDecryptFailed, when decrypting data fails.
+	ErrDecryption = errors.New("message authentication failed")
+	// ErrPathTooDeep is returned when a path has more components than the
+	// Store's MaxPathDepth allows.
+	ErrPathTooDeep = errors.New("path has too many components")
+	// ErrInconsistentStore is returned by Store.Validate when the internal tree
+	// of a Store is inconsistent.
+	ErrInconsistentStore = errors.New("inconsistent store")
+)
@@ -32,6 +32,25 @@ func Increment(nonce *storj.Nonce, amount int64) (truncated bool, err error) {
 	return incrementBytes(nonce[:], amount)
 }
 
+// DeriveNonce derives the content nonce of the segment at pos from the base
+// nonce. The segment index is incremented by one to avoid reusing the zero
+// nonce, which is used for the metadata encryption.
+func DeriveNonce(base storj.Nonce, pos storj.SegmentPosition) (storj.Nonce, error) {
+	if pos.PartNumber < 0 || pos.Index < 0 {
+		return storj.Nonce{}, Error.New("invalid segment position: %+v", pos)
+	}
+
+	nonce := base
+	truncated, err := Increment(&nonce, int64(pos.PartNumber)<<32|(int64(pos.Index)+1))
+	if err != nil {
+		return storj.Nonce{}, err
+	}
+	if truncated {
+		return storj.Nonce{}, Error.New("nonce truncated for segment position: %+v", pos)
+	}
+	return nonce, nil
+}
+
 // Encrypt encrypts data with the given cipher, key and nonce.
 func Encrypt(data []byte, cipher storj.CipherSuite, key *storj.Key, nonce *storj.Nonce) (cipherData []byte, err error) {
 	// Don't encrypt empty slice
@@ -124,6 +143,18 @@ func DecryptKey(keyToDecrypt storj.EncryptedPrivateKey, cipher storj.CipherSuite
 	return &decryptedKey, nil
 }
 
+// EncryptMetadata encrypts an object metadata blob with the given cipher,
+// content key and nonce. With EncNull the plaintext is returned unchanged.
+func EncryptMetadata(plaintext []byte, cipher storj.CipherSuite, key *storj.Key, nonce *storj.Nonce) ([]byte, error) {
+	return Encrypt(plaintext, cipher, key, nonce)
+}
+
+// DecryptMetadata decrypts an object metadata blob encrypted with
+// EncryptMetadata using the same cipher, content key and nonce.
+func DecryptMetadata(cipherData []byte, cipher storj.CipherSuite, key *storj.Key, nonce *storj.Nonce) ([]byte, error) {
+	return Decrypt(cipherData, cipher, key, nonce)
+}
+
 // DeriveKey derives new key from the given key and message using HMAC-SHA512.
 func DeriveKey(key *storj.Key, message string) (*storj.Key, error) {
 	mac := hmac.New(sha512.New, key[:])
@@ -142,6 +173,10 @@ func DeriveKey(key *storj.Key, message string) (*storj.Key, error) {
 // encrypting data with dataSize using a Transformer with the given encryption
 // parameters.
 func CalcEncryptedSize(dataSize int64, parameters storj.EncryptionParameters) (int64, error) {
+	if dataSize < 0 {
+		return 0, Error.New("negative data size %d", dataSize)
+	}
+
 	transformer, err := NewEncrypter(parameters.CipherSuite, new(storj.Key), new(storj.Nonce), int(parameters.BlockSize))
 	if err != nil {
 		return 0, err
@@ -154,3 +189,28 @@ func CalcEncryptedSize(dataSize int64, parameters storj.EncryptionParameters) (i
 
 	return encryptedSize, nil
 }
+
+// CalcDecryptedSize calculates the size of the plain data for cipher data with
+// encryptedSize using a Transformer with the given encryption parameters.
+//
+// The padding length is only stored inside the encrypted data, so the result is
+// the largest plain data size that encrypts to encryptedSize.
+func CalcDecryptedSize(encryptedSize int64, parameters storj.EncryptionParameters) (int64, error) {
+	transformer, err := NewEncrypter(parameters.CipherSuite, new(storj.Key), new(storj.Nonce), int(parameters.BlockSize))
+	if err != nil {
+		return 0, err
+	}
+
+	outBlockSize := int64(transformer.OutBlockSize())
+	if encryptedSize <= 0 || encryptedSize%outBlockSize != 0 {
+		return 0, Error.New("encrypted size %d is not a multiple of block size %d", encryptedSize, outBlockSize)
+	}
+
+	blocks := encryptedSize / outBlockSize
+	dataSize := blocks*int64(transformer.InBlockSize()) - uint32Size
+	if dataSize < 0 {
+		return 0, Error.New("encrypted size %d too small for padding", encryptedSize)
+	}
+
+	return dataSize, nil
+}
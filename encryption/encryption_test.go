@@ -57,6 +57,140 @@ func TestCalcEncryptedSize(t *testing.T) {
 	})
 }
 
+func TestCalcEncryptedSize_Blocks(t *testing.T) {
+	for _, cipher := range []storj.CipherSuite{storj.EncAESGCM, storj.EncSecretBox} {
+		parameters := storj.EncryptionParameters{CipherSuite: cipher, BlockSize: 1 * memory.KiB.Int32()}
+		// both ciphers use a 16 byte tag per block
+		inBlockSize := 1*memory.KiB.Int64() - 16
+
+		for _, test := range []struct {
+			dataSize int64
+			expected int64
+		}{
+			{0, 1 * memory.KiB.Int64()},
+			{inBlockSize - uint32Size, 1 * memory.KiB.Int64()},
+			{inBlockSize - uint32Size + 1, 2 * memory.KiB.Int64()},
+			{2*inBlockSize - uint32Size, 2 * memory.KiB.Int64()},
+			{2*inBlockSize - uint32Size + 100, 3 * memory.KiB.Int64()},
+		} {
+			size, err := encryption.CalcEncryptedSize(test.dataSize, parameters)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, size, "%d: %d", cipher, test.dataSize)
+		}
+
+		_, err := encryption.CalcEncryptedSize(-1, parameters)
+		require.Error(t, err)
+	}
+}
+
+func TestCalcDecryptedSize(t *testing.T) {
+	forAllCiphers(func(cipher storj.CipherSuite) {
+		parameters := storj.EncryptionParameters{CipherSuite: cipher, BlockSize: 1 * memory.KiB.Int32()}
+
+		for _, dataSize := range []int64{
+			0,
+			1,
+			1*memory.KiB.Int64() - uint32Size,
+			1 * memory.KiB.Int64(),
+			32*memory.KiB.Int64() + 100,
+		} {
+			encryptedSize, err := encryption.CalcEncryptedSize(dataSize, parameters)
+			require.NoError(t, err)
+
+			decryptedSize, err := encryption.CalcDecryptedSize(encryptedSize, parameters)
+			require.NoError(t, err)
+			assert.True(t, decryptedSize >= dataSize, "%d: %d < %d", cipher, decryptedSize, dataSize)
+
+			// the largest plain size must map back to the same encrypted size
+			roundTrip, err := encryption.CalcEncryptedSize(decryptedSize, parameters)
+			require.NoError(t, err)
+			assert.Equal(t, encryptedSize, roundTrip, "%d: %d", cipher, dataSize)
+
+			if cipher == storj.EncNull {
+				assert.Equal(t, dataSize, decryptedSize)
+			}
+		}
+	})
+
+	parameters := storj.EncryptionParameters{CipherSuite: storj.EncAESGCM, BlockSize: 1 * memory.KiB.Int32()}
+	for _, encryptedSize := range []int64{-1, 0, 1, 1*memory.KiB.Int64() + 1} {
+		_, err := encryption.CalcDecryptedSize(encryptedSize, parameters)
+		require.Error(t, err, encryptedSize)
+	}
+
+	_, err := encryption.CalcDecryptedSize(uint32Size-1, storj.EncryptionParameters{CipherSuite: storj.EncNull})
+	require.Error(t, err)
+}
+
+func TestDeriveNonce(t *testing.T) {
+	base := testrand.Nonce()
+
+	seen := map[storj.Nonce]storj.SegmentPosition{}
+	for _, pos := range []storj.SegmentPosition{
+		{PartNumber: 0, Index: 0},
+		{PartNumber: 0, Index: 1},
+		{PartNumber: 0, Index: 2},
+		{PartNumber: 1, Index: 0},
+		{PartNumber: 1, Index: 1},
+		{PartNumber: 2, Index: 0},
+		{PartNumber: 0, Index: 1 << 30},
+	} {
+		nonce, err := encryption.DeriveNonce(base, pos)
+		require.NoError(t, err)
+		assert.NotEqual(t, base, nonce)
+
+		again, err := encryption.DeriveNonce(base, pos)
+		require.NoError(t, err)
+		assert.Equal(t, nonce, again)
+
+		if other, ok := seen[nonce]; ok {
+			t.Fatalf("positions %+v and %+v derived the same nonce", pos, other)
+		}
+		seen[nonce] = pos
+	}
+
+	// matches the derivation used for the first segment of a single part upload
+	expected := base
+	_, err := encryption.Increment(&expected, 1)
+	require.NoError(t, err)
+	nonce, err := encryption.DeriveNonce(base, storj.SegmentPosition{})
+	require.NoError(t, err)
+	assert.Equal(t, expected, nonce)
+
+	_, err = encryption.DeriveNonce(base, storj.SegmentPosition{Index: -1})
+	require.Error(t, err)
+}
+
+func TestEncryptMetadata(t *testing.T) {
+	forAllCiphers(func(cipher storj.CipherSuite) {
+		key, nonce := testrand.Key(), testrand.Nonce()
+
+		for _, plaintext := range [][]byte{
+			{},
+			[]byte("a"),
+			testrand.BytesInt(1024),
+		} {
+			encrypted, err := encryption.EncryptMetadata(plaintext, cipher, &key, &nonce)
+			require.NoError(t, err, cipher)
+			if cipher == storj.EncNull || len(plaintext) == 0 {
+				assert.Equal(t, plaintext, encrypted, cipher)
+			} else {
+				assert.NotEqual(t, plaintext, encrypted, cipher)
+			}
+
+			decrypted, err := encryption.DecryptMetadata(encrypted, cipher, &key, &nonce)
+			require.NoError(t, err, cipher)
+			assert.Equal(t, plaintext, decrypted, cipher)
+
+			if cipher != storj.EncNull && len(plaintext) > 0 {
+				wrongKey := testrand.Key()
+				_, err = encryption.DecryptMetadata(encrypted, cipher, &wrongKey, &nonce)
+				assert.Error(t, err, cipher)
+			}
+		}
+	})
+}
+
 func forAllCiphers(test func(cipher storj.CipherSuite)) {
 	for _, cipher := range []storj.CipherSuite{
 		storj.EncNull,
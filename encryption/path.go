@@ -4,6 +4,7 @@
 package encryption
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha512"
 	"encoding/base64"
@@ -62,6 +63,27 @@ func EncryptPath(bucket string, path paths.Unencrypted, pathCipher storj.CipherS
 	return encryptPath(bucket, path, &pathCipher, store)
 }
 
+// EncryptPaths encrypts every path looking up keys and the cipher from the provided
+// store and bucket. The context is checked between paths; when it is canceled the
+// paths encrypted so far are returned together with the context error.
+func EncryptPaths(ctx context.Context, bucket string, unencPaths []paths.Unencrypted, store *Store) (
+	encPaths []paths.Encrypted, err error) {
+
+	encPaths = make([]paths.Encrypted, 0, len(unencPaths))
+	for _, path := range unencPaths {
+		if err := ctx.Err(); err != nil {
+			return encPaths, err
+		}
+
+		encPath, err := EncryptPathWithStoreCipher(bucket, path, store)
+		if err != nil {
+			return encPaths, err
+		}
+		encPaths = append(encPaths, encPath)
+	}
+	return encPaths, nil
+}
+
 func encryptPath(bucket string, path paths.Unencrypted, pathCipher *storj.CipherSuite, store *Store) (
 	encPath paths.Encrypted, err error) {
 
@@ -69,6 +91,9 @@ func encryptPath(bucket string, path paths.Unencrypted, pathCipher *storj.Cipher
 	if !path.Valid() {
 		return paths.Encrypted{}, nil
 	}
+	if err := store.checkPathDepth(path.Raw()); err != nil {
+		return paths.Encrypted{}, err
+	}
 
 	_, consumed, base := store.LookupUnencrypted(bucket, path)
 	if base == nil {
@@ -153,6 +178,42 @@ func DecryptPathWithStoreCipher(bucket string, path paths.Encrypted, store *Stor
 	return decryptPath(bucket, path, nil, store)
 }
 
+// DecryptedPath is a path yielded by a stream from NewDecryptingPathStream.
+// Err is set when the encrypted path could not be decrypted.
+type DecryptedPath struct {
+	Encrypted   paths.Encrypted
+	Unencrypted paths.Unencrypted
+	Err         error
+}
+
+// NewDecryptingPathStream returns a function that pulls the next path from src
+// and decrypts it looking up keys and the cipher from the provided store and
+// bucket. Paths that fail to decrypt are yielded with Err set, so that the rest
+// of the stream can still be read. It reports false once src is exhausted.
+func NewDecryptingPathStream(bucket string, store *Store, src func() (paths.Encrypted, bool)) func() (DecryptedPath, bool) {
+	return func() (DecryptedPath, bool) {
+		enc, ok := src()
+		if !ok {
+			return DecryptedPath{}, false
+		}
+
+		unenc, err := DecryptPathWithStoreCipher(bucket, enc, store)
+		return DecryptedPath{Encrypted: enc, Unencrypted: unenc, Err: err}, true
+	}
+}
+
+// VerifyEncryptedPath reports whether the path decrypts using the keys and cipher
+// from the provided store and bucket. It returns an error only when the store has
+// no key that could decrypt the path.
+func VerifyEncryptedPath(bucket string, enc paths.Encrypted, store *Store) (bool, error) {
+	if _, _, base := store.LookupEncrypted(bucket, enc); base == nil {
+		return false, errs.New("unable to find decryption base for: %q", enc)
+	}
+
+	_, err := decryptPath(bucket, enc, nil, store)
+	return err == nil, nil
+}
+
 // DecryptPath decrypts the path using the provided cipher and looking up keys from the
 // provided store and bucket.
 func DecryptPath(bucket string, path paths.Encrypted, pathCipher storj.CipherSuite, store *Store) (
@@ -168,6 +229,9 @@ func decryptPath(bucket string, path paths.Encrypted, pathCipher *storj.CipherSu
 	if !path.Valid() {
 		return paths.Unencrypted{}, nil
 	}
+	if err := store.checkPathDepth(path.Raw()); err != nil {
+		return paths.Unencrypted{}, err
+	}
 
 	_, consumed, base := store.LookupEncrypted(bucket, path)
 	if base == nil {
@@ -259,6 +323,10 @@ func DeriveContentKey(bucket string, path paths.Unencrypted, store *Store) (key
 // DerivePathKey returns the path key for the passed in path by looking up the
 // appropriate base key from the store and bucket and deriving the rest.
 func DerivePathKey(bucket string, path paths.Unencrypted, store *Store) (key *storj.Key, err error) {
+	if err := store.checkPathDepth(path.Raw()); err != nil {
+		return nil, err
+	}
+
 	_, consumed, base := store.LookupUnencrypted(bucket, path)
 	if base == nil {
 		return nil, errs.New("unable to find encryption base for: %s/%q", bucket, path)
@@ -302,6 +370,24 @@ func DerivePathKey(bucket string, path paths.Unencrypted, store *Store) (key *st
 	return key, nil
 }
 
+// DeriveKeyChain derives the path keys for every level of the path made of the
+// components, starting from the root key. The key at index i is the key
+// after deriving components[0] through components[i], the same way that
+// DerivePathKey derives keys below the base of a Store.
+func DeriveKeyChain(root storj.Key, components []string) ([]storj.Key, error) {
+	keys := make([]storj.Key, 0, len(components))
+	key := &root
+	for _, component := range components {
+		var err error
+		key, err = derivePathKeyComponent(key, component)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		keys = append(keys, *key)
+	}
+	return keys, nil
+}
+
 // derivePathKeyComponent derives a new key from the provided one using the component. It
 // should be preferred over DeriveKey when adding path components as it performs the
 // necessary transformation to the component.
@@ -5,13 +5,16 @@ package encryption
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"fmt"
+	"math"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/zeebo/errs"
 
 	"storj.io/common/paths"
 	"storj.io/common/storj"
@@ -63,6 +66,51 @@ func TestStoreEncryption(t *testing.T) {
 	})
 }
 
+func TestStoreEncryption_EncNull(t *testing.T) {
+	defaultKey := testrand.Key()
+	defaultStore := NewStore()
+	defaultStore.SetDefaultKey(&defaultKey)
+	defaultStore.SetDefaultPathCipher(storj.EncNull)
+
+	for name, store := range map[string]*Store{
+		"entry":   newStore(testrand.Key(), storj.EncNull),
+		"default": defaultStore,
+	} {
+		for i, rawPath := range []string{
+			"/",
+			"//",
+			"file.txt",
+			"file.txt/",
+			"fold1//file.txt",
+			"/fold1/fold2/fold3/file.txt",
+			"\x00\x01\xfe\xff/\x2e",
+		} {
+			errTag := fmt.Sprintf("test:%d path:%q store:%s", i, rawPath, name)
+			key := testrand.Key()
+
+			generic, err := EncryptPathRaw(rawPath, storj.EncNull, &key)
+			require.NoError(t, err, errTag)
+			require.Equal(t, rawPath, generic, errTag)
+
+			encPath, err := EncryptPathWithStoreCipher("bucket", paths.NewUnencrypted(rawPath), store)
+			require.NoError(t, err, errTag)
+			assert.Equal(t, generic, encPath.Raw(), errTag)
+
+			encPath, err = EncryptPath("bucket", paths.NewUnencrypted(rawPath), storj.EncNull, store)
+			require.NoError(t, err, errTag)
+			assert.Equal(t, generic, encPath.Raw(), errTag)
+
+			generic, err = DecryptPathRaw(rawPath, storj.EncNull, &key)
+			require.NoError(t, err, errTag)
+			require.Equal(t, rawPath, generic, errTag)
+
+			decPath, err := DecryptPathWithStoreCipher("bucket", paths.NewEncrypted(rawPath), store)
+			require.NoError(t, err, errTag)
+			assert.Equal(t, generic, decPath.Raw(), errTag)
+		}
+	}
+}
+
 func TestStorePrefixEncryption(t *testing.T) {
 	forAllCiphers(func(cipher storj.CipherSuite) {
 		for i, rawPath := range []string{
@@ -147,6 +195,206 @@ func TestStoreEncryption_BucketRoot(t *testing.T) {
 	})
 }
 
+// cancelAfterContext reports context.Canceled after Err has been called n times.
+type cancelAfterContext struct {
+	context.Context
+	n int
+}
+
+func (ctx *cancelAfterContext) Err() error {
+	if ctx.n <= 0 {
+		return context.Canceled
+	}
+	ctx.n--
+	return nil
+}
+
+func TestEncryptPaths(t *testing.T) {
+	forAllCiphers(func(cipher storj.CipherSuite) {
+		store := newStore(testrand.Key(), cipher)
+
+		var unencPaths []paths.Unencrypted
+		for i := 0; i < 10; i++ {
+			unencPaths = append(unencPaths, paths.NewUnencrypted(fmt.Sprintf("fold%d/file.txt", i)))
+		}
+
+		encPaths, err := EncryptPaths(context.Background(), "bucket", unencPaths, store)
+		require.NoError(t, err)
+		require.Len(t, encPaths, len(unencPaths))
+		for i, encPath := range encPaths {
+			expected, err := EncryptPathWithStoreCipher("bucket", unencPaths[i], store)
+			require.NoError(t, err)
+			assert.Equal(t, expected, encPath)
+		}
+
+		ctx := &cancelAfterContext{Context: context.Background(), n: 3}
+		partial, err := EncryptPaths(ctx, "bucket", unencPaths, store)
+		require.Equal(t, context.Canceled, err)
+		require.Len(t, partial, 3)
+		assert.Equal(t, encPaths[:3], partial)
+	})
+}
+
+func TestVerifyEncryptedPath(t *testing.T) {
+	for _, cipher := range []storj.CipherSuite{storj.EncAESGCM, storj.EncSecretBox} {
+		store := newStore(testrand.Key(), cipher)
+
+		enc, err := EncryptPathWithStoreCipher("bucket", paths.NewUnencrypted("a/b/c"), store)
+		require.NoError(t, err)
+
+		ok, err := VerifyEncryptedPath("bucket", enc, store)
+		require.NoError(t, err)
+		assert.True(t, ok, cipher)
+
+		for _, garbage := range []string{"garbage", "a/b/c", enc.Raw() + "x"} {
+			ok, err = VerifyEncryptedPath("bucket", paths.NewEncrypted(garbage), store)
+			require.NoError(t, err)
+			assert.False(t, ok, garbage)
+		}
+
+		// a path encrypted with another key is not part of the keyspace
+		other, err := EncryptPathWithStoreCipher("bucket", paths.NewUnencrypted("a/b/c"), newStore(testrand.Key(), cipher))
+		require.NoError(t, err)
+		ok, err = VerifyEncryptedPath("bucket", other, store)
+		require.NoError(t, err)
+		assert.False(t, ok, cipher)
+
+		_, err = VerifyEncryptedPath("other", enc, store)
+		require.Error(t, err)
+	}
+}
+
+func TestDeriveContentKey(t *testing.T) {
+	store := newStore(testrand.Key(), storj.EncAESGCM)
+
+	key1, err := DeriveContentKey("bucket", paths.NewUnencrypted("a/b/c"), store)
+	require.NoError(t, err)
+	key2, err := DeriveContentKey("bucket", paths.NewUnencrypted("a/b/d"), store)
+	require.NoError(t, err)
+	assert.NotEqual(t, key1, key2)
+
+	again, err := DeriveContentKey("bucket", paths.NewUnencrypted("a/b/c"), store)
+	require.NoError(t, err)
+	assert.Equal(t, key1, again)
+
+	pathKey, err := DerivePathKey("bucket", paths.NewUnencrypted("a/b/c"), store)
+	require.NoError(t, err)
+	assert.NotEqual(t, pathKey, key1)
+
+	_, err = DeriveContentKey("other", paths.NewUnencrypted("a/b/c"), store)
+	require.Error(t, err)
+}
+
+func TestDeriveKeyChain(t *testing.T) {
+	root := testrand.Key()
+	store := newStore(root, storj.EncAESGCM)
+
+	chain, err := DeriveKeyChain(root, []string{"a", "b", "c"})
+	require.NoError(t, err)
+	require.Len(t, chain, 3)
+
+	for i, path := range []string{"a", "a/b", "a/b/c"} {
+		pathKey, err := DerivePathKey("bucket", paths.NewUnencrypted(path), store)
+		require.NoError(t, err)
+		assert.Equal(t, *pathKey, chain[i], path)
+	}
+
+	// the default key includes the bucket in the derivation
+	defaultStore := NewStore()
+	defaultStore.SetDefaultKey(&root)
+
+	chain, err = DeriveKeyChain(root, []string{"bucket", "a", "b", "c"})
+	require.NoError(t, err)
+	pathKey, err := DerivePathKey("bucket", paths.NewUnencrypted("a/b/c"), defaultStore)
+	require.NoError(t, err)
+	assert.Equal(t, *pathKey, chain[len(chain)-1])
+
+	chain, err = DeriveKeyChain(root, nil)
+	require.NoError(t, err)
+	assert.Empty(t, chain)
+}
+
+func TestAllCipherSuites(t *testing.T) {
+	// roundTrip encrypts and decrypts a path with a store using the cipher.
+	roundTrip := func(cipher storj.CipherSuite) error {
+		store := NewStore()
+		if err := store.AddWithCipher("bucket", paths.Unencrypted{}, paths.Encrypted{}, testrand.Key(), cipher); err != nil {
+			return err
+		}
+
+		path := paths.NewUnencrypted("a/b/c")
+		enc, err := EncryptPathWithStoreCipher("bucket", path, store)
+		if err != nil {
+			return err
+		}
+		dec, err := DecryptPathWithStoreCipher("bucket", enc, store)
+		if err != nil {
+			return err
+		}
+		if dec != path {
+			return errs.New("decrypted %q, expected %q", dec, path)
+		}
+		return nil
+	}
+
+	for _, cipher := range storj.AllCipherSuites() {
+		assert.NoError(t, roundTrip(cipher), cipher)
+		assert.True(t, cipher.IsValid(), cipher)
+	}
+
+	// the list contains exactly the suites the store round-trips
+	var working []storj.CipherSuite
+	for cipher := 0; cipher <= math.MaxUint8; cipher++ {
+		if roundTrip(storj.CipherSuite(cipher)) == nil {
+			working = append(working, storj.CipherSuite(cipher))
+		}
+	}
+	assert.Equal(t, storj.AllCipherSuites(), working)
+}
+
+func TestDecryptingPathStream(t *testing.T) {
+	store := newStore(testrand.Key(), storj.EncAESGCM)
+
+	var encrypted []paths.Encrypted
+	for _, raw := range []string{"a", "b/c", "garbage", "d/e/f"} {
+		enc, err := EncryptPathWithStoreCipher("bucket", paths.NewUnencrypted(raw), store)
+		require.NoError(t, err)
+		if raw == "garbage" {
+			enc = paths.NewEncrypted(raw)
+		}
+		encrypted = append(encrypted, enc)
+	}
+
+	next := NewDecryptingPathStream("bucket", store, func() (paths.Encrypted, bool) {
+		if len(encrypted) == 0 {
+			return paths.Encrypted{}, false
+		}
+		enc := encrypted[0]
+		encrypted = encrypted[1:]
+		return enc, true
+	})
+
+	var decrypted []string
+	var failed []string
+	for {
+		item, ok := next()
+		if !ok {
+			break
+		}
+		if item.Err != nil {
+			failed = append(failed, item.Encrypted.Raw())
+			continue
+		}
+		decrypted = append(decrypted, item.Unencrypted.Raw())
+	}
+
+	assert.Equal(t, []string{"a", "b/c", "d/e/f"}, decrypted)
+	assert.Equal(t, []string{"garbage"}, failed)
+
+	_, ok := next()
+	assert.False(t, ok)
+}
+
 func forAllCiphers(test func(cipher storj.CipherSuite)) {
 	for _, cipher := range []storj.CipherSuite{
 		storj.EncNull,
@@ -99,7 +99,7 @@ func (s *secretboxDecrypter) Transform(out, in []byte, blockNum int64) ([]byte,
 	}
 	rv, success := secretbox.Open(out, in, nonce.Raw(), s.key.Raw())
 	if !success {
-		return nil, ErrDecryptFailed.New("")
+		return nil, ErrDecryptFailed.Wrap(ErrDecryption)
 	}
 	return rv, nil
 }
@@ -113,7 +113,7 @@ func EncryptSecretBox(data []byte, key *storj.Key, nonce *storj.Nonce) (cipherDa
 func DecryptSecretBox(cipherData []byte, key *storj.Key, nonce *storj.Nonce) (data []byte, err error) {
 	data, success := secretbox.Open(nil, cipherData, nonce.Raw(), key.Raw())
 	if !success {
-		return nil, ErrDecryptFailed.New("")
+		return nil, ErrDecryptFailed.Wrap(ErrDecryption)
 	}
 	return data, nil
 }
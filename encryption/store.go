@@ -4,7 +4,11 @@
 package encryption
 
 import (
-	"github.com/zeebo/errs"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
 
 	"storj.io/common/paths"
 	"storj.io/common/storj"
@@ -35,6 +39,9 @@ type Store struct {
 	roots             map[string]*node
 	defaultKey        *storj.Key
 	defaultPathCipher storj.CipherSuite
+	bucketDefaultKeys map[string]storj.Key
+	bucketNames       map[string]string // bucketKey => bucket as first added
+	options           Options
 
 	// EncryptionBypass makes it so we can interoperate with
 	// the network without having encryption keys. paths will be encrypted but
@@ -72,9 +79,71 @@ func (b *Base) clone() *Base {
 	return &bc
 }
 
+// Options configures the behavior of a Store.
+type Options struct {
+	// CaseInsensitiveBuckets makes the Store treat bucket names that differ
+	// only in case as the same bucket. The bucket is reported with the
+	// spelling it was first added with.
+	CaseInsensitiveBuckets bool
+
+	// MaxPathDepth limits the number of components of the paths that can be
+	// added, encrypted, decrypted or have keys derived. Zero means unlimited.
+	MaxPathDepth int
+}
+
 // NewStore constructs a Store.
 func NewStore() *Store {
-	return &Store{roots: make(map[string]*node)}
+	return NewStoreWithOptions(Options{})
+}
+
+// NewStoreWithOptions constructs a Store with the given options.
+func NewStoreWithOptions(options Options) *Store {
+	return &Store{
+		roots:             make(map[string]*node),
+		bucketDefaultKeys: make(map[string]storj.Key),
+		bucketNames:       make(map[string]string),
+		options:           options,
+	}
+}
+
+// bucketKey returns the key used for bucket in the roots map.
+func (s *Store) bucketKey(bucket string) string {
+	if s.options.CaseInsensitiveBuckets {
+		return strings.ToLower(bucket)
+	}
+	return bucket
+}
+
+// addBucketName remembers the spelling of the bucket, unless another spelling
+// of it has been added before.
+func (s *Store) addBucketName(bucket string) {
+	if _, ok := s.bucketNames[s.bucketKey(bucket)]; !ok {
+		s.bucketNames[s.bucketKey(bucket)] = bucket
+	}
+}
+
+// bucketName returns the bucket name to report for the key of the roots map.
+func (s *Store) bucketName(bucketKey string) string {
+	if bucket, ok := s.bucketNames[bucketKey]; ok {
+		return bucket
+	}
+	return bucketKey
+}
+
+// checkPathDepth returns an error if the raw path has more components than
+// the MaxPathDepth option allows.
+func (s *Store) checkPathDepth(raw string) error {
+	if s.options.MaxPathDepth <= 0 {
+		return nil
+	}
+	depth := 0
+	for iter := paths.NewIterator(raw); !iter.Done(); iter.Next() {
+		depth++
+		if depth > s.options.MaxPathDepth {
+			return Error.Wrap(ErrPathTooDeep)
+		}
+	}
+	return nil
 }
 
 // newNode constructs a node.
@@ -87,16 +156,118 @@ func newNode() *node {
 	}
 }
 
+// clone returns a deep copy of the Store.
+func (s *Store) clone() *Store {
+	sc := &Store{
+		roots:             make(map[string]*node, len(s.roots)),
+		defaultPathCipher: s.defaultPathCipher,
+		bucketDefaultKeys: make(map[string]storj.Key, len(s.bucketDefaultKeys)),
+		bucketNames:       make(map[string]string, len(s.bucketNames)),
+		options:           s.options,
+		EncryptionBypass:  s.EncryptionBypass,
+	}
+	if s.defaultKey != nil {
+		defaultKey := *s.defaultKey
+		sc.defaultKey = &defaultKey
+	}
+	for bucket, root := range s.roots {
+		sc.roots[bucket] = root.clone()
+	}
+	for bucket, key := range s.bucketDefaultKeys {
+		sc.bucketDefaultKeys[bucket] = key
+	}
+	for bucketKey, bucket := range s.bucketNames {
+		sc.bucketNames[bucketKey] = bucket
+	}
+	return sc
+}
+
+// clone returns a deep copy of the node and its children.
+func (n *node) clone() *node {
+	nc := newNode()
+	nc.base = n.base.clone()
+	for unencPart, encPart := range n.unencMap {
+		child := n.unenc[unencPart].clone()
+		nc.unencMap[unencPart] = encPart
+		nc.encMap[encPart] = unencPart
+		nc.unenc[unencPart] = child
+		nc.enc[encPart] = child
+	}
+	return nc
+}
+
+// RotateDefaultKey returns a copy of the Store that uses newKey as its default key.
+// Lookups that fall back to the default key derive their keys from newKey in the
+// returned Store. Entries added with an explicit key are not rotated and keep
+// their original keys. Bucket default keys set with SetBucketDefaultKey are not
+// rotated either, so lookups in those buckets keep using them.
+func (s *Store) RotateDefaultKey(newKey *storj.Key) (*Store, error) {
+	if newKey == nil {
+		return nil, Error.New("new default key is nil")
+	}
+	rotated := s.clone()
+	key := *newKey
+	rotated.defaultKey = &key
+	return rotated, nil
+}
+
+// RekeyBucket changes the key of the entries of the bucket whose key is oldKey
+// to newKey and returns how many entries were changed. Entries with other
+// keys, entries of other buckets and the default keys are untouched.
+func (s *Store) RekeyBucket(bucket string, oldKey, newKey storj.Key) (int, error) {
+	root, ok := s.roots[s.bucketKey(bucket)]
+	if !ok {
+		return 0, Error.New("no entries for bucket %q", bucket)
+	}
+	if oldKey == newKey {
+		return 0, nil
+	}
+	return root.rekey(oldKey, newKey), nil
+}
+
+// rekey replaces oldKey with newKey in the base of the node and of all its
+// descendants and returns the number of replaced keys.
+func (n *node) rekey(oldKey, newKey storj.Key) (count int) {
+	if n.base != nil && n.base.Key == oldKey {
+		n.base.Key = newKey
+		count++
+	}
+
+	// recurse down only the unenc map, as the enc map should be the same.
+	for _, child := range n.unenc {
+		count += child.rekey(oldKey, newKey)
+	}
+	return count
+}
+
 // SetDefaultKey adds a default key to be returned for any lookup that does not match a bucket.
 func (s *Store) SetDefaultKey(defaultKey *storj.Key) {
 	s.defaultKey = defaultKey
 }
 
+// SetBucketDefaultKey adds a default key to be returned for any lookup in the bucket
+// that does not match an entry. It takes precedence over the key set with
+// SetDefaultKey. Setting a nil key removes the bucket default key.
+func (s *Store) SetBucketDefaultKey(bucket string, key *storj.Key) {
+	if key == nil {
+		delete(s.bucketDefaultKeys, s.bucketKey(bucket))
+		return
+	}
+	s.addBucketName(bucket)
+	s.bucketDefaultKeys[s.bucketKey(bucket)] = *key
+}
+
 // GetDefaultKey returns the default key, or nil if none has been set.
 func (s *Store) GetDefaultKey() *storj.Key {
 	return s.defaultKey
 }
 
+// DefaultKey returns the default key set with SetDefaultKey and whether one
+// has been set.
+func (s *Store) DefaultKey() (*storj.Key, bool) {
+	return s.defaultKey, s.defaultKey != nil
+}
+
 // SetDefaultPathCipher  adds a default path cipher to be returned for any lookup that does not match a bucket.
 func (s *Store) SetDefaultPathCipher(defaultPathCipher storj.CipherSuite) {
 	s.defaultPathCipher = defaultPathCipher
@@ -114,7 +285,14 @@ func (s *Store) Add(bucket string, unenc paths.Unencrypted, enc paths.Encrypted,
 
 // AddWithCipher creates a mapping from the unencrypted path to the encrypted path and key with the given cipher.
 func (s *Store) AddWithCipher(bucket string, unenc paths.Unencrypted, enc paths.Encrypted, key storj.Key, pathCipher storj.CipherSuite) error {
-	root, ok := s.roots[bucket]
+	if err := s.checkPathDepth(unenc.Raw()); err != nil {
+		return err
+	}
+	if err := s.checkPathDepth(enc.Raw()); err != nil {
+		return err
+	}
+
+	root, ok := s.roots[s.bucketKey(bucket)]
 	if !ok {
 		root = newNode()
 	}
@@ -130,14 +308,18 @@ func (s *Store) AddWithCipher(bucket string, unenc paths.Unencrypted, enc paths.
 	}
 
 	// only update the root for the bucket if the add was successful.
-	s.roots[bucket] = root
+	s.addBucketName(bucket)
+	s.roots[s.bucketKey(bucket)] = root
 	return nil
 }
 
 // add places the paths and base into the node tree structure.
 func (n *node) add(unenc, enc paths.Iterator, base *Base) error {
-	if unenc.Done() != enc.Done() {
-		return errs.New("encrypted and unencrypted paths had different number of components")
+	if unenc.Done() && !enc.Done() {
+		return Error.Wrap(ErrTooManyEncryptedParts)
+	}
+	if !unenc.Done() && enc.Done() {
+		return Error.Wrap(ErrTooManyUnencryptedParts)
 	}
 
 	// If we're done walking the paths, this node must have the provided base.
@@ -149,10 +331,10 @@ func (n *node) add(unenc, enc paths.Iterator, base *Base) error {
 	// Walk to the next parts and ensure they're consistent with previous additions.
 	unencPart, encPart := unenc.Next(), enc.Next()
 	if exUnencPart, ok := n.encMap[encPart]; ok && exUnencPart != unencPart {
-		return errs.New("conflicting encrypted parts for unencrypted path")
+		return Error.Wrap(ErrPathMismatch)
 	}
 	if exEncPart, ok := n.unencMap[unencPart]; ok && exEncPart != encPart {
-		return errs.New("conflicting encrypted parts for unencrypted path")
+		return Error.Wrap(ErrPathMismatch)
 	}
 
 	// Look up the child node. Since we're sure the unenc and enc mappings are
@@ -182,14 +364,16 @@ func (n *node) add(unenc, enc paths.Iterator, base *Base) error {
 func (s *Store) LookupUnencrypted(bucket string, path paths.Unencrypted) (
 	revealed map[string]string, consumed paths.Unencrypted, base *Base) {
 
-	root, ok := s.roots[bucket]
+	root, ok := s.roots[s.bucketKey(bucket)]
 	if ok {
 		var rawConsumed string
 		revealed, rawConsumed, base = root.lookup(path.Iterator(), "", nil, true)
 		consumed = paths.NewUnencrypted(rawConsumed)
 	}
-	if base == nil && s.defaultKey != nil {
-		return nil, paths.Unencrypted{}, s.defaultBase()
+	if base == nil {
+		if defaultBase := s.defaultBase(bucket); defaultBase != nil {
+			return nil, paths.Unencrypted{}, defaultBase
+		}
 	}
 	return revealed, consumed, base.clone()
 }
@@ -200,21 +384,77 @@ func (s *Store) LookupUnencrypted(bucket string, path paths.Unencrypted) (
 func (s *Store) LookupEncrypted(bucket string, path paths.Encrypted) (
 	revealed map[string]string, consumed paths.Encrypted, base *Base) {
 
-	root, ok := s.roots[bucket]
+	root, ok := s.roots[s.bucketKey(bucket)]
 	if ok {
 		var rawConsumed string
 		revealed, rawConsumed, base = root.lookup(path.Iterator(), "", nil, false)
 		consumed = paths.NewEncrypted(rawConsumed)
 	}
-	if base == nil && s.defaultKey != nil {
-		return nil, paths.Encrypted{}, s.defaultBase()
+	if base == nil {
+		if defaultBase := s.defaultBase(bucket); defaultBase != nil {
+			return nil, paths.Encrypted{}, defaultBase
+		}
 	}
 	return revealed, consumed, base.clone()
 }
 
-func (s *Store) defaultBase() *Base {
+// LookupUnencryptedTrace is like LookupUnencrypted, but it returns the revealed
+// map of every node traversed from the root of the bucket down to the most
+// matching node, mapping encrypted to unencrypted path components. The first
+// map is the one of the root, and there is one more for every matched path
+// component.
+func (s *Store) LookupUnencryptedTrace(bucket string, path paths.Unencrypted) ([]map[string]string, *Base) {
+	_, _, base := s.LookupUnencrypted(bucket, path)
+
+	n, ok := s.roots[s.bucketKey(bucket)]
+	if !ok {
+		return nil, base
+	}
+
+	var trace []map[string]string
+	iter := path.Iterator()
+	for {
+		revealed := make(map[string]string, len(n.encMap))
+		for encPart, unencPart := range n.encMap {
+			revealed[encPart] = unencPart
+		}
+		trace = append(trace, revealed)
+
+		if iter.Done() {
+			return trace, base
+		}
+		child, ok := n.unenc[iter.Next()]
+		if !ok {
+			return trace, base
+		}
+		n = child
+	}
+}
+
+// DeepestMatch returns the number of components of the unencrypted path that
+// matched an entry added to the Store, along with the base that
+// LookupUnencrypted returns for the path. The depth is 0 if no entry matched
+// and the lookup fell back to a default key.
+func (s *Store) DeepestMatch(bucket string, unenc paths.Unencrypted) (matchedDepth int, base *Base) {
+	_, _, base = s.LookupUnencrypted(bucket, unenc)
+	if base == nil || base.Default {
+		return 0, base
+	}
+	return base.Unencrypted.NumComponents(), base
+}
+
+// defaultBase returns the base for lookups in the bucket that don't match an entry,
+// or nil if neither the bucket nor the global default key has been set.
+func (s *Store) defaultBase(bucket string) *Base {
+	key, ok := s.bucketDefaultKeys[s.bucketKey(bucket)]
+	if !ok {
+		if s.defaultKey == nil {
+			return nil
+		}
+		key = *s.defaultKey
+	}
 	return &Base{
-		Key:        *s.defaultKey,
+		Key:        key,
 		PathCipher: s.defaultPathCipher,
 		Default:    true,
 	}
@@ -251,11 +491,227 @@ func (n *node) lookup(path paths.Iterator, bestConsumed string, bestBase *Base,
 	return child.lookup(path, bestConsumed, bestBase, unenc)
 }
 
+// RevealedChildren returns the mapping of encrypted to unencrypted path components
+// of the known children of the unencrypted path. It is the same as the revealed map
+// reported by LookupUnencrypted when the whole path matches.
+func (s *Store) RevealedChildren(bucket string, unenc paths.Unencrypted) (map[string]string, error) {
+	root, ok := s.roots[s.bucketKey(bucket)]
+	if !ok {
+		return nil, Error.New("no entries for bucket %q", bucket)
+	}
+
+	n := root.find(unenc.Iterator(), true)
+	if n == nil {
+		return nil, Error.New("no entries at path %s/%q", bucket, unenc)
+	}
+
+	revealed := make(map[string]string, len(n.encMap))
+	for encPart, unencPart := range n.encMap {
+		revealed[encPart] = unencPart
+	}
+	return revealed, nil
+}
+
+// IsUnencryptedBucket returns true when every entry of the bucket uses EncNull
+// and so does the default base for the bucket, if there is one. It returns
+// false when the Store has neither entries nor a default key for the bucket.
+func (s *Store) IsUnencryptedBucket(bucket string) bool {
+	defaultBase := s.defaultBase(bucket)
+	if defaultBase != nil && defaultBase.PathCipher != storj.EncNull {
+		return false
+	}
+
+	root, ok := s.roots[s.bucketKey(bucket)]
+	if !ok {
+		return defaultBase != nil
+	}
+
+	return root.allUseCipher(storj.EncNull)
+}
+
+// allUseCipher returns true if the base of the node and of all its descendants
+// use the path cipher.
+func (n *node) allUseCipher(pathCipher storj.CipherSuite) bool {
+	if n.base != nil && n.base.PathCipher != pathCipher {
+		return false
+	}
+
+	// recurse down only the unenc map, as the enc map should be the same.
+	for _, child := range n.unenc {
+		if !child.allUseCipher(pathCipher) {
+			return false
+		}
+	}
+	return true
+}
+
+// Subset returns a new Store containing only the entries of the bucket at or
+// under any of the prefixes. Prefixes are matched by whole path components.
+// The returned Store keeps the options and default path cipher, but none of
+// the default keys.
+func (s *Store) Subset(bucket string, prefixes []paths.Unencrypted) (*Store, error) {
+	root, ok := s.roots[s.bucketKey(bucket)]
+	if !ok {
+		return nil, Error.New("no entries for bucket %q", bucket)
+	}
+
+	subset := NewStoreWithOptions(s.options)
+	subset.defaultPathCipher = s.defaultPathCipher
+	subset.EncryptionBypass = s.EncryptionBypass
+
+	for _, prefix := range prefixes {
+		n := root.find(prefix.Iterator(), true)
+		if n == nil {
+			continue
+		}
+
+		err := n.iterateWithCipher(func(bucket string, unenc paths.Unencrypted, enc paths.Encrypted, key storj.Key, pathCipher storj.CipherSuite) error {
+			return subset.AddWithCipher(bucket, unenc, enc, key, pathCipher)
+		}, bucket)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return subset, nil
+}
+
+// CoveringEntries returns the minimal set of entries of the bucket at or under
+// the prefix that together hold the base keys for everything under it: an
+// entry is returned only when none of its ancestors under the prefix is an
+// entry itself. A listing of the prefix can fan out over the returned entries.
+// Prefixes are matched by whole path components and the entries are sorted by
+// unencrypted path.
+func (s *Store) CoveringEntries(bucket string, prefix paths.Unencrypted) ([]StoreEntry, error) {
+	root, ok := s.roots[s.bucketKey(bucket)]
+	if !ok {
+		return nil, Error.New("no entries for bucket %q", bucket)
+	}
+
+	n := root.find(prefix.Iterator(), true)
+	if n == nil {
+		return nil, nil
+	}
+
+	var entries []StoreEntry
+	n.covering(func(base *Base) {
+		entries = append(entries, StoreEntry{
+			Bucket:      s.bucketName(s.bucketKey(bucket)),
+			Unencrypted: base.Unencrypted,
+			Encrypted:   base.Encrypted,
+			Key:         base.Key,
+			PathCipher:  base.PathCipher,
+		})
+	})
+	sortStoreEntries(entries)
+	return entries, nil
+}
+
+// covering calls the callback with the base of the node if it has one, and
+// otherwise recurses to its children.
+func (n *node) covering(fn func(base *Base)) {
+	if n.base != nil {
+		fn(n.base)
+		return
+	}
+
+	// recurse down only the unenc map, as the enc map should be the same.
+	for _, child := range n.unenc {
+		child.covering(fn)
+	}
+}
+
+// Validate checks that the internal tree of the Store is consistent: the
+// mappings between unencrypted and encrypted components must agree with each
+// other and every entry's paths must match its position in the tree. It is
+// meant for Stores built from untrusted data. The first inconsistency is
+// returned as an error wrapping ErrInconsistentStore.
+func (s *Store) Validate() error {
+	buckets := make([]string, 0, len(s.roots))
+	for bucket := range s.roots {
+		buckets = append(buckets, bucket)
+	}
+	sort.Strings(buckets)
+
+	for _, bucket := range buckets {
+		if err := s.roots[bucket].validate(nil, nil); err != nil {
+			return Error.Wrap(fmt.Errorf("%w: bucket %q: %v", ErrInconsistentStore, s.bucketName(bucket), err))
+		}
+	}
+	return nil
+}
+
+// validate checks the node and its descendants. unenc and enc are the
+// components of the paths leading to the node.
+func (n *node) validate(unenc, enc []string) error {
+	if n.base != nil {
+		if got := n.base.Unencrypted.NumComponents(); got != len(unenc) {
+			return fmt.Errorf("entry at %q has %d unencrypted components, expected %d",
+				strings.Join(unenc, "/"), got, len(unenc))
+		}
+		if got := n.base.Encrypted.NumComponents(); got != len(enc) {
+			return fmt.Errorf("entry at %q has %d encrypted components, expected %d",
+				strings.Join(unenc, "/"), got, len(enc))
+		}
+		if n.base.Unencrypted.Raw() != strings.Join(unenc, "/") || n.base.Encrypted.Raw() != strings.Join(enc, "/") {
+			return fmt.Errorf("entry at %q has mismatched paths %q => %q",
+				strings.Join(unenc, "/"), n.base.Unencrypted.Raw(), n.base.Encrypted.Raw())
+		}
+	}
+
+	if len(n.unenc) != len(n.unencMap) || len(n.enc) != len(n.encMap) || len(n.unenc) != len(n.enc) {
+		return fmt.Errorf("node at %q has mismatched child counts", strings.Join(unenc, "/"))
+	}
+
+	unencParts := make([]string, 0, len(n.unenc))
+	for unencPart := range n.unenc {
+		unencParts = append(unencParts, unencPart)
+	}
+	sort.Strings(unencParts)
+
+	for _, unencPart := range unencParts {
+		child := n.unenc[unencPart]
+		encPart, ok := n.unencMap[unencPart]
+		if !ok {
+			return fmt.Errorf("node at %q has no encrypted part for %q", strings.Join(unenc, "/"), unencPart)
+		}
+		if n.encMap[encPart] != unencPart {
+			return fmt.Errorf("node at %q maps %q and %q inconsistently", strings.Join(unenc, "/"), unencPart, encPart)
+		}
+		if n.enc[encPart] != child {
+			return fmt.Errorf("node at %q has different children for %q and %q", strings.Join(unenc, "/"), unencPart, encPart)
+		}
+
+		err := child.validate(append(unenc[:len(unenc):len(unenc)], unencPart), append(enc[:len(enc):len(enc)], encPart))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// find walks the path down the node tree structure and returns the node at the
+// end of it, or nil if there is no such node.
+func (n *node) find(path paths.Iterator, unenc bool) *node {
+	for !path.Done() {
+		children := n.enc
+		if unenc {
+			children = n.unenc
+		}
+
+		child, ok := children[path.Next()]
+		if !ok {
+			return nil
+		}
+		n = child
+	}
+	return n
+}
+
 // Iterate executes the callback with every value that has been Added to the Store.
 // NOTE: This call is lossy! Please upgrade any code paths to use IterateWithCipher!
 func (s *Store) Iterate(fn func(string, paths.Unencrypted, paths.Encrypted, storj.Key) error) error {
 	for bucket, root := range s.roots {
-		if err := root.iterate(fn, bucket); err != nil {
+		if err := root.iterate(fn, s.bucketName(bucket)); err != nil {
 			return err
 		}
 	}
@@ -285,7 +741,7 @@ func (n *node) iterate(fn func(string, paths.Unencrypted, paths.Encrypted, storj
 // IterateWithCipher executes the callback with every value that has been Added to the Store.
 func (s *Store) IterateWithCipher(fn func(string, paths.Unencrypted, paths.Encrypted, storj.Key, storj.CipherSuite) error) error {
 	for bucket, root := range s.roots {
-		if err := root.iterateWithCipher(fn, bucket); err != nil {
+		if err := root.iterateWithCipher(fn, s.bucketName(bucket)); err != nil {
 			return err
 		}
 	}
@@ -311,3 +767,204 @@ func (n *node) iterateWithCipher(fn func(string, paths.Unencrypted, paths.Encryp
 
 	return nil
 }
+
+// Fingerprint returns a SHA-256 digest of the contents of the Store: every
+// entry with its bucket, paths, key and path cipher, the default key, the
+// bucket default keys and the default path cipher. The contents are hashed in
+// a canonical order, so Stores with the same contents have the same
+// fingerprint regardless of the order they were built in.
+func (s *Store) Fingerprint() ([]byte, error) {
+	var entries []StoreEntry
+	err := s.IterateWithCipher(func(bucket string, unenc paths.Unencrypted, enc paths.Encrypted, key storj.Key, pathCipher storj.CipherSuite) error {
+		entries = append(entries, StoreEntry{bucket, unenc, enc, key, pathCipher})
+		return nil
+	})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	sortStoreEntries(entries)
+
+	h := sha256.New()
+	writeString := func(value string) {
+		var length [binary.MaxVarintLen64]byte
+		_, _ = h.Write(length[:binary.PutUvarint(length[:], uint64(len(value)))])
+		_, _ = h.Write([]byte(value))
+	}
+
+	if s.defaultKey != nil {
+		writeString("default key")
+		_, _ = h.Write(s.defaultKey[:])
+	}
+	writeString("default path cipher")
+	_, _ = h.Write([]byte{byte(s.defaultPathCipher)})
+
+	buckets := make([]string, 0, len(s.bucketDefaultKeys))
+	for bucket := range s.bucketDefaultKeys {
+		buckets = append(buckets, bucket)
+	}
+	sort.Strings(buckets)
+	for _, bucket := range buckets {
+		key := s.bucketDefaultKeys[bucket]
+		writeString("bucket default key")
+		writeString(s.bucketName(bucket))
+		_, _ = h.Write(key[:])
+	}
+
+	for _, entry := range entries {
+		writeString("entry")
+		writeString(entry.Bucket)
+		writeString(entry.Unencrypted.Raw())
+		writeString(entry.Encrypted.Raw())
+		_, _ = h.Write(entry.Key[:])
+		_, _ = h.Write([]byte{byte(entry.PathCipher)})
+	}
+
+	return h.Sum(nil), nil
+}
+
+// redactedKey is printed in place of every key by Redacted.
+const redactedKey = "<redacted>"
+
+// Redacted returns a description of the entries of the Store that is safe to
+// log: the bucket, unencrypted and encrypted path and path cipher of every
+// entry are printed, but keys are replaced with a placeholder. For every
+// entry it shows whether its key is the default key of the bucket.
+func (s *Store) Redacted() string {
+	var entries []StoreEntry
+	for bucket, root := range s.roots {
+		_ = root.iterateWithCipher(func(bucket string, unenc paths.Unencrypted, enc paths.Encrypted, key storj.Key, pathCipher storj.CipherSuite) error {
+			entries = append(entries, StoreEntry{bucket, unenc, enc, key, pathCipher})
+			return nil
+		}, s.bucketName(bucket))
+	}
+	sortStoreEntries(entries)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "default key: %s, default path cipher: %s\n",
+		redactedKeyState(s.defaultKey != nil), cipherName(s.defaultPathCipher))
+
+	buckets := make([]string, 0, len(s.bucketDefaultKeys))
+	for bucket := range s.bucketDefaultKeys {
+		buckets = append(buckets, bucket)
+	}
+	sort.Strings(buckets)
+	for _, bucket := range buckets {
+		fmt.Fprintf(&b, "bucket %q default key: %s\n", s.bucketName(bucket), redactedKey)
+	}
+
+	for _, entry := range entries {
+		usesDefault := false
+		if base := s.defaultBase(entry.Bucket); base != nil {
+			usesDefault = base.Key == entry.Key
+		}
+		fmt.Fprintf(&b, "bucket %q: %q => %q key: %s, path cipher: %s, default key: %t\n",
+			entry.Bucket, entry.Unencrypted.Raw(), entry.Encrypted.Raw(),
+			redactedKey, cipherName(entry.PathCipher), usesDefault)
+	}
+	return b.String()
+}
+
+// String implements fmt.Stringer. It returns the same as Redacted, so that
+// keys are not leaked when a Store is logged.
+func (s *Store) String() string { return s.Redacted() }
+
+// redactedKeyState describes whether a key is set without revealing it.
+func redactedKeyState(set bool) string {
+	if set {
+		return redactedKey
+	}
+	return "<unset>"
+}
+
+// cipherName returns a readable name for the path cipher.
+func cipherName(cipher storj.CipherSuite) string {
+	switch cipher {
+	case storj.EncUnspecified:
+		return "unspecified"
+	case storj.EncNull:
+		return "null"
+	case storj.EncAESGCM:
+		return "aesgcm"
+	case storj.EncSecretBox:
+		return "secretbox"
+	case storj.EncNullBase64URL:
+		return "null-base64url"
+	default:
+		return fmt.Sprintf("unknown(%d)", cipher)
+	}
+}
+
+// StoreEntry is a single mapping that has been Added to a Store.
+type StoreEntry struct {
+	Bucket      string
+	Unencrypted paths.Unencrypted
+	Encrypted   paths.Encrypted
+	Key         storj.Key
+	PathCipher  storj.CipherSuite
+}
+
+// DiffStores compares the entries of two Stores. Entries are matched by bucket
+// and unencrypted path. Entries present in only one of the Stores are returned in
+// onlyA or onlyB, and entries present in both whose encrypted path, key or path
+// cipher differ are returned in conflicting, using the entry from a.
+// Default keys are not compared.
+func DiffStores(a, b *Store) (onlyA, onlyB, conflicting []StoreEntry, err error) {
+	type entryKey struct {
+		bucket string
+		unenc  paths.Unencrypted
+	}
+
+	collect := func(s *Store) (map[entryKey]StoreEntry, error) {
+		entries := make(map[entryKey]StoreEntry)
+		err := s.IterateWithCipher(func(bucket string, unenc paths.Unencrypted, enc paths.Encrypted, key storj.Key, pathCipher storj.CipherSuite) error {
+			entries[entryKey{bucket, unenc}] = StoreEntry{
+				Bucket:      bucket,
+				Unencrypted: unenc,
+				Encrypted:   enc,
+				Key:         key,
+				PathCipher:  pathCipher,
+			}
+			return nil
+		})
+		return entries, err
+	}
+
+	entriesA, err := collect(a)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	entriesB, err := collect(b)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	for key, entryA := range entriesA {
+		entryB, ok := entriesB[key]
+		switch {
+		case !ok:
+			onlyA = append(onlyA, entryA)
+		case entryA != entryB:
+			conflicting = append(conflicting, entryA)
+		}
+	}
+	for key, entryB := range entriesB {
+		if _, ok := entriesA[key]; !ok {
+			onlyB = append(onlyB, entryB)
+		}
+	}
+
+	sortStoreEntries(onlyA)
+	sortStoreEntries(onlyB)
+	sortStoreEntries(conflicting)
+	return onlyA, onlyB, conflicting, nil
+}
+
+// sortStoreEntries sorts entries by bucket and unencrypted path.
+func sortStoreEntries(entries []StoreEntry) {
+	sort.Slice(entries, func(i, k int) bool {
+		if entries[i].Bucket != entries[k].Bucket {
+			return entries[i].Bucket < entries[k].Bucket
+		}
+		return entries[i].Unencrypted.Less(entries[k].Unencrypted)
+	})
+}
@@ -4,7 +4,9 @@
 package encryption
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -136,15 +138,40 @@ func TestStoreErrors(t *testing.T) {
 		up := paths.NewUnencrypted
 
 		// Too many encrypted parts
-		require.Error(t, s.AddWithCipher("b1", up("u1"), ep("e1/e2/e3"), storj.Key{}, pathCipher))
+		err := s.AddWithCipher("b1", up("u1"), ep("e1/e2/e3"), storj.Key{}, pathCipher)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrTooManyEncryptedParts))
+		assert.True(t, Error.Has(err))
 
 		// Too many unencrypted parts
-		require.Error(t, s.AddWithCipher("b1", up("u1/u2/u3"), ep("e1"), storj.Key{}, pathCipher))
+		err = s.AddWithCipher("b1", up("u1/u2/u3"), ep("e1"), storj.Key{}, pathCipher)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrTooManyUnencryptedParts))
 
 		// Mismatches
 		require.NoError(t, s.AddWithCipher("b1", up("u1"), ep("e1"), storj.Key{}, pathCipher))
-		require.Error(t, s.AddWithCipher("b1", up("u2"), ep("e1"), storj.Key{}, pathCipher))
-		require.Error(t, s.AddWithCipher("b1", up("u1"), ep("f1"), storj.Key{}, pathCipher))
+		err = s.AddWithCipher("b1", up("u2"), ep("e1"), storj.Key{}, pathCipher)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrPathMismatch))
+		err = s.AddWithCipher("b1", up("u1"), ep("f1"), storj.Key{}, pathCipher)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrPathMismatch))
+		assert.False(t, errors.Is(err, ErrTooManyEncryptedParts))
+	}
+}
+
+func TestDecryptionErrors(t *testing.T) {
+	key, wrongKey := toKey("key"), toKey("wrong")
+	nonce := storj.Nonce{}
+
+	for _, cipher := range []storj.CipherSuite{storj.EncAESGCM, storj.EncSecretBox} {
+		encrypted, err := Encrypt([]byte("data"), cipher, &key, &nonce)
+		require.NoError(t, err)
+
+		_, err = Decrypt(encrypted, cipher, &wrongKey, &nonce)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrDecryption), cipher)
+		assert.True(t, ErrDecryptFailed.Has(err), cipher)
 	}
 }
 
@@ -209,3 +236,678 @@ func TestStoreIterate(t *testing.T) {
 		require.Equal(t, expected, got)
 	}
 }
+
+func TestStoreCaseInsensitiveBuckets(t *testing.T) {
+	ep := paths.NewEncrypted
+	up := paths.NewUnencrypted
+
+	for _, caseInsensitive := range []bool{false, true} {
+		s := NewStoreWithOptions(Options{CaseInsensitiveBuckets: caseInsensitive})
+		require.NoError(t, s.AddWithCipher("B1", up("u1"), ep("e1"), toKey("k1"), storj.EncAESGCM))
+
+		_, _, base := s.LookupUnencrypted("b1", up("u1"))
+		_, _, encBase := s.LookupEncrypted("b1", ep("e1"))
+		if caseInsensitive {
+			require.NotNil(t, base)
+			assert.Equal(t, toKey("k1"), base.Key)
+			require.NotNil(t, encBase)
+			assert.Equal(t, toKey("k1"), encBase.Key)
+		} else {
+			assert.Nil(t, base)
+			assert.Nil(t, encBase)
+		}
+
+		_, _, base = s.LookupUnencrypted("B1", up("u1"))
+		require.NotNil(t, base)
+		assert.Equal(t, toKey("k1"), base.Key)
+	}
+}
+
+func TestStoreCaseInsensitiveBuckets_Names(t *testing.T) {
+	ep := paths.NewEncrypted
+	up := paths.NewUnencrypted
+
+	s := NewStoreWithOptions(Options{CaseInsensitiveBuckets: true})
+	require.NoError(t, s.AddWithCipher("B1", up("u1"), ep("e1"), toKey("k1"), storj.EncAESGCM))
+	require.NoError(t, s.AddWithCipher("b1", up("u2"), ep("e2"), toKey("k2"), storj.EncAESGCM))
+	bucketKey := toKey("bucket")
+	s.SetBucketDefaultKey("Bucket2", &bucketKey)
+
+	// buckets are reported with the spelling they were first added with
+	var buckets []string
+	require.NoError(t, s.IterateWithCipher(func(bucket string, _ paths.Unencrypted, _ paths.Encrypted, _ storj.Key, _ storj.CipherSuite) error {
+		buckets = append(buckets, bucket)
+		return nil
+	}))
+	assert.Equal(t, []string{"B1", "B1"}, buckets)
+
+	require.NoError(t, s.Iterate(func(bucket string, _ paths.Unencrypted, _ paths.Encrypted, _ storj.Key) error {
+		assert.Equal(t, "B1", bucket)
+		return nil
+	}))
+
+	entries, err := s.CoveringEntries("b1", up(""))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "B1", entries[0].Bucket)
+
+	redacted := s.Redacted()
+	assert.Contains(t, redacted, `bucket "B1": "u1"`)
+	assert.Contains(t, redacted, `bucket "Bucket2" default key`)
+
+	onlyA, _, _, err := DiffStores(s, NewStore())
+	require.NoError(t, err)
+	require.Len(t, onlyA, 2)
+	assert.Equal(t, "B1", onlyA[0].Bucket)
+
+	// clones keep the names
+	rotated, err := s.RotateDefaultKey(&bucketKey)
+	require.NoError(t, err)
+	entries, err = rotated.CoveringEntries("b1", up(""))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "B1", entries[0].Bucket)
+}
+
+func TestStoreRotateDefaultKey(t *testing.T) {
+	ep := paths.NewEncrypted
+	up := paths.NewUnencrypted
+
+	s := NewStore()
+	oldKey, newKey := toKey("old"), toKey("new")
+	s.SetDefaultKey(&oldKey)
+	s.SetDefaultPathCipher(storj.EncAESGCM)
+	require.NoError(t, s.AddWithCipher("b1", up("u1"), ep("e1"), toKey("k1"), storj.EncAESGCM))
+
+	rotated, err := s.RotateDefaultKey(&newKey)
+	require.NoError(t, err)
+
+	// the original store is unchanged
+	assert.Equal(t, oldKey, *s.GetDefaultKey())
+	assert.Equal(t, newKey, *rotated.GetDefaultKey())
+
+	// lookups relying on the default key now use the new key
+	_, _, base := rotated.LookupUnencrypted("b2", up("u1"))
+	require.NotNil(t, base)
+	assert.True(t, base.Default)
+	assert.Equal(t, newKey, base.Key)
+
+	oldEnc, err := EncryptPathWithStoreCipher("b2", up("u1/u2"), s)
+	require.NoError(t, err)
+	newEnc, err := EncryptPathWithStoreCipher("b2", up("u1/u2"), rotated)
+	require.NoError(t, err)
+	assert.NotEqual(t, oldEnc, newEnc)
+
+	// explicit entries keep their keys
+	_, _, base = rotated.LookupUnencrypted("b1", up("u1"))
+	require.NotNil(t, base)
+	assert.False(t, base.Default)
+	assert.Equal(t, toKey("k1"), base.Key)
+
+	oldEnc, err = EncryptPathWithStoreCipher("b1", up("u1/u2"), s)
+	require.NoError(t, err)
+	newEnc, err = EncryptPathWithStoreCipher("b1", up("u1/u2"), rotated)
+	require.NoError(t, err)
+	assert.Equal(t, oldEnc, newEnc)
+
+	// modifying the rotated store does not affect the original
+	require.NoError(t, rotated.AddWithCipher("b1", up("u3"), ep("e3"), toKey("k3"), storj.EncAESGCM))
+	_, _, base = s.LookupUnencrypted("b1", up("u3"))
+	assert.True(t, base.Default)
+
+	_, err = s.RotateDefaultKey(nil)
+	require.Error(t, err)
+	require.True(t, Error.Has(err))
+
+	// bucket default keys are not rotated
+	bucketKey := toKey("bucket")
+	s.SetBucketDefaultKey("b3", &bucketKey)
+	rotated, err = s.RotateDefaultKey(&newKey)
+	require.NoError(t, err)
+	_, _, base = rotated.LookupUnencrypted("b3", up("u1"))
+	require.NotNil(t, base)
+	assert.True(t, base.Default)
+	assert.Equal(t, bucketKey, base.Key)
+}
+
+func TestStoreRevealedChildren(t *testing.T) {
+	s := NewStore()
+	ep := paths.NewEncrypted
+	up := paths.NewUnencrypted
+
+	require.NoError(t, s.AddWithCipher("b1", up("u1/u2/u3"), ep("e1/e2/e3"), toKey("k3"), storj.EncAESGCM))
+	require.NoError(t, s.AddWithCipher("b1", up("u1/u2/u3/u4"), ep("e1/e2/e3/e4"), toKey("k4"), storj.EncAESGCM))
+	require.NoError(t, s.AddWithCipher("b1", up("u1/u5"), ep("e1/e5"), toKey("k5"), storj.EncAESGCM))
+	require.NoError(t, s.AddWithCipher("b1", up("u6"), ep("e6"), toKey("k6"), storj.EncAESGCM))
+
+	revealed, err := s.RevealedChildren("b1", up("u1"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"e2": "u2", "e5": "u5"}, revealed)
+
+	lookupRevealed, _, _ := s.LookupUnencrypted("b1", up("u1"))
+	assert.Equal(t, lookupRevealed, revealed)
+
+	revealed, err = s.RevealedChildren("b1", up("u1/u2/u3"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"e4": "u4"}, revealed)
+
+	revealed, err = s.RevealedChildren("b1", up("u1/u2/u3/u4"))
+	require.NoError(t, err)
+	assert.Empty(t, revealed)
+
+	revealed, err = s.RevealedChildren("b1", paths.Unencrypted{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"e1": "u1", "e6": "u6"}, revealed)
+
+	// modifying the result does not modify the store
+	revealed["e7"] = "u7"
+	revealed, err = s.RevealedChildren("b1", paths.Unencrypted{})
+	require.NoError(t, err)
+	assert.Len(t, revealed, 2)
+
+	_, err = s.RevealedChildren("b1", up("u7"))
+	require.Error(t, err)
+	_, err = s.RevealedChildren("b2", up("u1"))
+	require.Error(t, err)
+}
+
+func TestDiffStores(t *testing.T) {
+	ep := paths.NewEncrypted
+	up := paths.NewUnencrypted
+
+	a, b := NewStore(), NewStore()
+	for _, s := range []*Store{a, b} {
+		require.NoError(t, s.AddWithCipher("b1", up("u1"), ep("e1"), toKey("k1"), storj.EncAESGCM))
+		require.NoError(t, s.AddWithCipher("b2", up("u1/u2"), ep("e1/e2"), toKey("k2"), storj.EncAESGCM))
+	}
+
+	require.NoError(t, a.AddWithCipher("b1", up("u3"), ep("e3"), toKey("k3"), storj.EncAESGCM))
+	require.NoError(t, b.AddWithCipher("b3", up("u4"), ep("e4"), toKey("k4"), storj.EncAESGCM))
+
+	require.NoError(t, a.AddWithCipher("b1", up("u5"), ep("e5"), toKey("k5"), storj.EncAESGCM))
+	require.NoError(t, b.AddWithCipher("b1", up("u5"), ep("e5"), toKey("other"), storj.EncAESGCM))
+
+	onlyA, onlyB, conflicting, err := DiffStores(a, b)
+	require.NoError(t, err)
+	assert.Equal(t, []StoreEntry{{"b1", up("u3"), ep("e3"), toKey("k3"), storj.EncAESGCM}}, onlyA)
+	assert.Equal(t, []StoreEntry{{"b3", up("u4"), ep("e4"), toKey("k4"), storj.EncAESGCM}}, onlyB)
+	assert.Equal(t, []StoreEntry{{"b1", up("u5"), ep("e5"), toKey("k5"), storj.EncAESGCM}}, conflicting)
+
+	onlyA, onlyB, conflicting, err = DiffStores(a, a)
+	require.NoError(t, err)
+	assert.Empty(t, onlyA)
+	assert.Empty(t, onlyB)
+	assert.Empty(t, conflicting)
+}
+
+func TestStoreBucketDefaultKey(t *testing.T) {
+	ep := paths.NewEncrypted
+	up := paths.NewUnencrypted
+
+	s := NewStore()
+	globalKey, key1, key2 := toKey("global"), toKey("bucket1"), toKey("bucket2")
+	s.SetDefaultKey(&globalKey)
+	s.SetBucketDefaultKey("b1", &key1)
+	s.SetBucketDefaultKey("b2", &key2)
+	require.NoError(t, s.Add("b1", up("u1"), ep("e1"), toKey("k1")))
+
+	for _, test := range []struct {
+		bucket    string
+		path      string
+		expected  storj.Key
+		isDefault bool
+	}{
+		{"b1", "u1", toKey("k1"), false},
+		{"b1", "u2", key1, true},
+		{"b2", "u1", key2, true},
+		{"b3", "u1", globalKey, true},
+	} {
+		_, _, base := s.LookupUnencrypted(test.bucket, up(test.path))
+		require.NotNil(t, base, test.bucket)
+		assert.Equal(t, test.expected, base.Key, test.bucket)
+		assert.Equal(t, test.isDefault, base.Default, test.bucket)
+
+		_, _, base = s.LookupEncrypted(test.bucket, ep(test.path))
+		require.NotNil(t, base, test.bucket)
+		if test.isDefault {
+			assert.Equal(t, test.expected, base.Key, test.bucket)
+		}
+	}
+
+	rotated, err := s.RotateDefaultKey(&key2)
+	require.NoError(t, err)
+	_, _, base := rotated.LookupUnencrypted("b1", up("u2"))
+	require.NotNil(t, base)
+	assert.Equal(t, key1, base.Key)
+
+	// without a global default only buckets with a default key resolve
+	s.SetDefaultKey(nil)
+	_, _, base = s.LookupUnencrypted("b3", up("u1"))
+	assert.Nil(t, base)
+
+	s.SetBucketDefaultKey("b2", nil)
+	_, _, base = s.LookupUnencrypted("b2", up("u1"))
+	assert.Nil(t, base)
+}
+
+func TestStoreSubset(t *testing.T) {
+	ep := paths.NewEncrypted
+	up := paths.NewUnencrypted
+
+	s := NewStore()
+	defaultKey := toKey("default")
+	s.SetDefaultKey(&defaultKey)
+	s.SetDefaultPathCipher(storj.EncAESGCM)
+	abortIfError(s.Add("b1", up("u1/u2/u3"), ep("e1/e2/e3"), toKey("k3")))
+	abortIfError(s.Add("b1", up("u1/u2/u3/u4"), ep("e1/e2/e3/e4"), toKey("k4")))
+	abortIfError(s.Add("b1", up("u1/u5"), ep("e1/e5"), toKey("k5")))
+	abortIfError(s.Add("b1", up("u6"), ep("e6"), toKey("k6")))
+	abortIfError(s.Add("b1", up("u6/u7/u8"), ep("e6/e7/e8"), toKey("k8")))
+	abortIfError(s.Add("b2", up("u1"), ep("e1'"), toKey("k1")))
+
+	collect := func(s *Store) []StoreEntry {
+		var entries []StoreEntry
+		require.NoError(t, s.IterateWithCipher(func(bucket string, unenc paths.Unencrypted, enc paths.Encrypted, key storj.Key, pathCipher storj.CipherSuite) error {
+			entries = append(entries, StoreEntry{bucket, unenc, enc, key, pathCipher})
+			return nil
+		}))
+		sortStoreEntries(entries)
+		return entries
+	}
+
+	subset, err := s.Subset("b1", []paths.Unencrypted{up("u1/u2")})
+	require.NoError(t, err)
+	assert.Equal(t, []StoreEntry{
+		{"b1", up("u1/u2/u3"), ep("e1/e2/e3"), toKey("k3"), storj.EncAESGCM},
+		{"b1", up("u1/u2/u3/u4"), ep("e1/e2/e3/e4"), toKey("k4"), storj.EncAESGCM},
+	}, collect(subset))
+	assert.Nil(t, subset.GetDefaultKey())
+	assert.Equal(t, storj.EncAESGCM, subset.GetDefaultPathCipher())
+
+	// prefixes match whole components and entries at the prefix are included
+	subset, err = s.Subset("b1", []paths.Unencrypted{up("u1/u5"), up("u6/u7"), up("u1/u2/u3/u"), up("u9")})
+	require.NoError(t, err)
+	assert.Equal(t, []StoreEntry{
+		{"b1", up("u1/u5"), ep("e1/e5"), toKey("k5"), storj.EncAESGCM},
+		{"b1", up("u6/u7/u8"), ep("e6/e7/e8"), toKey("k8"), storj.EncAESGCM},
+	}, collect(subset))
+
+	_, err = s.Subset("b3", []paths.Unencrypted{up("u1")})
+	require.Error(t, err)
+}
+
+func TestStoreRekeyBucket(t *testing.T) {
+	ep := paths.NewEncrypted
+	up := paths.NewUnencrypted
+
+	s := NewStore()
+	s.SetDefaultPathCipher(storj.EncAESGCM)
+	defaultKey := toKey("old")
+	s.SetDefaultKey(&defaultKey)
+	abortIfError(s.Add("b1", up("u1"), ep("e1"), toKey("old")))
+	abortIfError(s.Add("b1", up("u1/u2"), ep("e1/e2"), toKey("old")))
+	abortIfError(s.Add("b1", up("u3"), ep("e3"), toKey("other")))
+	abortIfError(s.Add("b2", up("u1"), ep("e1"), toKey("old")))
+
+	changed, err := s.RekeyBucket("b1", toKey("old"), toKey("new"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, changed)
+
+	var entries []StoreEntry
+	require.NoError(t, s.IterateWithCipher(func(bucket string, unenc paths.Unencrypted, enc paths.Encrypted, key storj.Key, pathCipher storj.CipherSuite) error {
+		entries = append(entries, StoreEntry{bucket, unenc, enc, key, pathCipher})
+		return nil
+	}))
+	sortStoreEntries(entries)
+	assert.Equal(t, []StoreEntry{
+		{"b1", up("u1"), ep("e1"), toKey("new"), storj.EncAESGCM},
+		{"b1", up("u1/u2"), ep("e1/e2"), toKey("new"), storj.EncAESGCM},
+		{"b1", up("u3"), ep("e3"), toKey("other"), storj.EncAESGCM},
+		{"b2", up("u1"), ep("e1"), toKey("old"), storj.EncAESGCM},
+	}, entries)
+	assert.Equal(t, toKey("old"), *s.GetDefaultKey())
+
+	changed, err = s.RekeyBucket("b1", toKey("old"), toKey("new"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, changed)
+
+	_, err = s.RekeyBucket("b3", toKey("old"), toKey("new"))
+	require.Error(t, err)
+}
+
+func TestStoreLookupUnencryptedTrace(t *testing.T) {
+	ep := paths.NewEncrypted
+	up := paths.NewUnencrypted
+
+	s := NewStore()
+	abortIfError(s.AddWithCipher("b1", up("u1/u2/u3"), ep("e1/e2/e3"), toKey("k3"), storj.EncAESGCM))
+	abortIfError(s.AddWithCipher("b1", up("u1/u2/u3/u4"), ep("e1/e2/e3/e4"), toKey("k4"), storj.EncAESGCM))
+	abortIfError(s.AddWithCipher("b1", up("u1/u5"), ep("e1/e5"), toKey("k5"), storj.EncAESGCM))
+
+	trace, base := s.LookupUnencryptedTrace("b1", up("u1/u2/u3"))
+	assert.Equal(t, []map[string]string{
+		{"e1": "u1"},
+		{"e2": "u2", "e5": "u5"},
+		{"e3": "u3"},
+		{"e4": "u4"},
+	}, trace)
+	_, _, expected := s.LookupUnencrypted("b1", up("u1/u2/u3"))
+	assert.Equal(t, expected, base)
+	assert.Equal(t, toKey("k3"), base.Key)
+
+	// the last map is the revealed map reported by LookupUnencrypted
+	for _, path := range []string{"", "u1", "u1/u2", "u1/u2/u3/u4"} {
+		trace, _ = s.LookupUnencryptedTrace("b1", up(path))
+		revealed, _, _ := s.LookupUnencrypted("b1", up(path))
+		assert.Equal(t, revealed, trace[len(trace)-1], path)
+	}
+
+	// a path leaving the tree stops at the last matching node
+	trace, base = s.LookupUnencryptedTrace("b1", up("u1/u2/u3/u6/u7"))
+	assert.Len(t, trace, 4)
+	assert.Equal(t, toKey("k3"), base.Key)
+
+	trace, base = s.LookupUnencryptedTrace("b2", up("u1"))
+	assert.Nil(t, trace)
+	assert.Nil(t, base)
+}
+
+func TestStoreDefaultKey(t *testing.T) {
+	s := NewStore()
+
+	key, ok := s.DefaultKey()
+	assert.False(t, ok)
+	assert.Nil(t, key)
+
+	defaultKey := toKey("default")
+	s.SetDefaultKey(&defaultKey)
+
+	key, ok = s.DefaultKey()
+	assert.True(t, ok)
+	require.NotNil(t, key)
+	assert.Equal(t, defaultKey, *key)
+
+	s.SetDefaultKey(nil)
+	key, ok = s.DefaultKey()
+	assert.False(t, ok)
+	assert.Nil(t, key)
+}
+
+func TestStoreDeepestMatch(t *testing.T) {
+	ep := paths.NewEncrypted
+	up := paths.NewUnencrypted
+
+	s := NewStore()
+	abortIfError(s.AddWithCipher("b1", up("u1"), ep("e1"), toKey("k1"), storj.EncAESGCM))
+	abortIfError(s.AddWithCipher("b1", up("u1/u2/u3/u4"), ep("e1/e2/e3/e4"), toKey("k4"), storj.EncAESGCM))
+
+	depth, base := s.DeepestMatch("b1", up("u1/u2/u3/u4"))
+	assert.Equal(t, 4, depth)
+	assert.Equal(t, toKey("k4"), base.Key)
+
+	depth, base = s.DeepestMatch("b1", up("u1/u2/u3/u4/u5"))
+	assert.Equal(t, 4, depth)
+	assert.Equal(t, toKey("k4"), base.Key)
+
+	depth, base = s.DeepestMatch("b1", up("u1/u2"))
+	assert.Equal(t, 1, depth)
+	assert.Equal(t, toKey("k1"), base.Key)
+
+	depth, base = s.DeepestMatch("b1", up("u6"))
+	assert.Equal(t, 0, depth)
+	assert.Nil(t, base)
+
+	s.SetDefaultKey(&storj.Key{})
+	depth, base = s.DeepestMatch("b2", up("u1/u2"))
+	assert.Equal(t, 0, depth)
+	assert.True(t, base.Default)
+}
+
+func TestStoreCoveringEntries(t *testing.T) {
+	ep := paths.NewEncrypted
+	up := paths.NewUnencrypted
+
+	s := NewStore()
+	s.SetDefaultPathCipher(storj.EncAESGCM)
+	abortIfError(s.Add("b1", up("u1/u2/u3"), ep("e1/e2/e3"), toKey("k3")))
+	abortIfError(s.Add("b1", up("u1/u2/u3/u4"), ep("e1/e2/e3/e4"), toKey("k4")))
+	abortIfError(s.Add("b1", up("u1/u5"), ep("e1/e5"), toKey("k5")))
+	abortIfError(s.Add("b1", up("u6"), ep("e6"), toKey("k6")))
+	abortIfError(s.Add("b2", up("u1"), ep("e1'"), toKey("k1")))
+
+	entries, err := s.CoveringEntries("b1", up("u1"))
+	require.NoError(t, err)
+	assert.Equal(t, []StoreEntry{
+		{"b1", up("u1/u2/u3"), ep("e1/e2/e3"), toKey("k3"), storj.EncAESGCM},
+		{"b1", up("u1/u5"), ep("e1/e5"), toKey("k5"), storj.EncAESGCM},
+	}, entries)
+
+	// an entry at the prefix covers everything under it
+	entries, err = s.CoveringEntries("b1", up("u1/u2/u3"))
+	require.NoError(t, err)
+	assert.Equal(t, []StoreEntry{
+		{"b1", up("u1/u2/u3"), ep("e1/e2/e3"), toKey("k3"), storj.EncAESGCM},
+	}, entries)
+
+	entries, err = s.CoveringEntries("b1", up(""))
+	require.NoError(t, err)
+	assert.Equal(t, []StoreEntry{
+		{"b1", up("u1/u2/u3"), ep("e1/e2/e3"), toKey("k3"), storj.EncAESGCM},
+		{"b1", up("u1/u5"), ep("e1/e5"), toKey("k5"), storj.EncAESGCM},
+		{"b1", up("u6"), ep("e6"), toKey("k6"), storj.EncAESGCM},
+	}, entries)
+
+	// prefixes match whole components
+	entries, err = s.CoveringEntries("b1", up("u1/u"))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = s.CoveringEntries("b3", up("u1"))
+	require.Error(t, err)
+}
+
+func TestStoreIsUnencryptedBucket(t *testing.T) {
+	ep := paths.NewEncrypted
+	up := paths.NewUnencrypted
+
+	s := NewStore()
+	abortIfError(s.AddWithCipher("null", up("u1"), ep("u1"), toKey("k1"), storj.EncNull))
+	abortIfError(s.AddWithCipher("null", up("u1/u2"), ep("u1/u2"), toKey("k2"), storj.EncNull))
+	abortIfError(s.AddWithCipher("mixed", up("u1"), ep("u1"), toKey("k1"), storj.EncNull))
+	abortIfError(s.AddWithCipher("mixed", up("u1/u2"), ep("u1/e2"), toKey("k2"), storj.EncAESGCM))
+
+	assert.True(t, s.IsUnencryptedBucket("null"))
+	assert.False(t, s.IsUnencryptedBucket("mixed"))
+	assert.False(t, s.IsUnencryptedBucket("unknown"))
+
+	// the default base is taken into account
+	defaultKey := toKey("default")
+	s.SetDefaultKey(&defaultKey)
+	s.SetDefaultPathCipher(storj.EncNull)
+	assert.True(t, s.IsUnencryptedBucket("null"))
+	assert.True(t, s.IsUnencryptedBucket("unknown"))
+
+	s.SetDefaultPathCipher(storj.EncAESGCM)
+	assert.False(t, s.IsUnencryptedBucket("null"))
+	assert.False(t, s.IsUnencryptedBucket("unknown"))
+}
+
+func TestStoreRedacted(t *testing.T) {
+	ep := paths.NewEncrypted
+	up := paths.NewUnencrypted
+
+	defaultKey := toKey("default-key")
+	bucketKey := toKey("bucket-key")
+	entryKey := toKey("entry-key")
+
+	s := NewStore()
+	s.SetDefaultKey(&defaultKey)
+	s.SetDefaultPathCipher(storj.EncAESGCM)
+	s.SetBucketDefaultKey("b2", &bucketKey)
+	abortIfError(s.Add("b1", up("u1"), ep("e1"), entryKey))
+	abortIfError(s.Add("b1", up("u1/u2"), ep("e1/e2"), defaultKey))
+	abortIfError(s.AddWithCipher("b2", up("u3"), ep("u3"), bucketKey, storj.EncNull))
+
+	redacted := s.Redacted()
+	assert.Equal(t, redacted, s.String())
+	assert.Equal(t, redacted, fmt.Sprint(s))
+
+	assert.Equal(t, ""+
+		"default key: <redacted>, default path cipher: aesgcm\n"+
+		"bucket \"b2\" default key: <redacted>\n"+
+		"bucket \"b1\": \"u1\" => \"e1\" key: <redacted>, path cipher: aesgcm, default key: false\n"+
+		"bucket \"b1\": \"u1/u2\" => \"e1/e2\" key: <redacted>, path cipher: aesgcm, default key: true\n"+
+		"bucket \"b2\": \"u3\" => \"u3\" key: <redacted>, path cipher: null, default key: true\n",
+		redacted)
+
+	for _, key := range []storj.Key{defaultKey, bucketKey, entryKey} {
+		assert.NotContains(t, redacted, strings.TrimRight(string(key[:]), "\x00"))
+		assert.NotContains(t, redacted, fmt.Sprintf("%x", key[:8]))
+		assert.NotContains(t, redacted, fmt.Sprint(key[:8]))
+	}
+
+	assert.Equal(t, "default key: <unset>, default path cipher: unspecified\n", NewStore().Redacted())
+}
+
+func TestStoreMaxPathDepth(t *testing.T) {
+	ep := paths.NewEncrypted
+	up := paths.NewUnencrypted
+
+	s := NewStoreWithOptions(Options{MaxPathDepth: 3})
+	defaultKey := toKey("default")
+	s.SetDefaultKey(&defaultKey)
+	s.SetDefaultPathCipher(storj.EncAESGCM)
+
+	require.NoError(t, s.Add("b1", up("u1/u2/u3"), ep("e1/e2/e3"), toKey("k3")))
+
+	err := s.Add("b1", up("u1/u2/u3/u4"), ep("e1/e2/e3/e4"), toKey("k4"))
+	require.True(t, errors.Is(err, ErrPathTooDeep), err)
+	err = s.Add("b1", up("u1/u2/u3/"), ep("e1/e2/e3/"), toKey("k4"))
+	require.True(t, errors.Is(err, ErrPathTooDeep), err)
+
+	_, err = EncryptPathWithStoreCipher("b1", up("u1/u2/u3"), s)
+	require.NoError(t, err)
+	_, err = EncryptPathWithStoreCipher("b1", up("u1/u2/u3/u4"), s)
+	require.True(t, errors.Is(err, ErrPathTooDeep), err)
+
+	_, err = DecryptPathWithStoreCipher("b1", ep("e1/e2/e3"), s)
+	require.NoError(t, err)
+	_, err = DecryptPathWithStoreCipher("b1", ep("e1/e2/e3/e4"), s)
+	require.True(t, errors.Is(err, ErrPathTooDeep), err)
+
+	_, err = DerivePathKey("b1", up("a/b/c"), s)
+	require.NoError(t, err)
+	_, err = DerivePathKey("b1", up("a/b/c/d"), s)
+	require.True(t, errors.Is(err, ErrPathTooDeep), err)
+
+	// unlimited by default
+	s = NewStore()
+	require.NoError(t, s.Add("b1", up("u1/u2/u3/u4/u5/u6"), ep("e1/e2/e3/e4/e5/e6"), toKey("k6")))
+}
+
+func TestStoreValidate(t *testing.T) {
+	ep := paths.NewEncrypted
+	up := paths.NewUnencrypted
+
+	newValid := func() *Store {
+		s := NewStore()
+		abortIfError(s.Add("b1", up(""), ep(""), toKey("k0")))
+		abortIfError(s.Add("b1", up("u1/u2/u3"), ep("e1/e2/e3"), toKey("k3")))
+		abortIfError(s.Add("b1", up("u1/u2/u3/u4"), ep("e1/e2/e3/e4"), toKey("k4")))
+		abortIfError(s.Add("b1", up("u1/u5/"), ep("e1/e5/"), toKey("k5")))
+		abortIfError(s.Add("b2", up("u1"), ep("e1'"), toKey("k1")))
+		return s
+	}
+	require.NoError(t, newValid().Validate())
+	require.NoError(t, NewStore().Validate())
+
+	for name, corrupt := range map[string]func(s *Store){
+		"too many encrypted parts": func(s *Store) {
+			s.roots["b1"].unenc["u1"].unenc["u2"].unenc["u3"].base.Encrypted = ep("e1/e2/e3/e4")
+		},
+		"too many unencrypted parts": func(s *Store) {
+			s.roots["b2"].unenc["u1"].base.Unencrypted = up("u1/u2")
+		},
+		"mismatched path": func(s *Store) {
+			s.roots["b2"].unenc["u1"].base.Encrypted = ep("other")
+		},
+		"mismatched maps": func(s *Store) {
+			s.roots["b1"].unenc["u1"].encMap["e2"] = "other"
+		},
+		"missing child": func(s *Store) {
+			delete(s.roots["b1"].unenc["u1"].enc, "e5")
+		},
+	} {
+		s := newValid()
+		corrupt(s)
+		err := s.Validate()
+		require.Error(t, err, name)
+		require.True(t, errors.Is(err, ErrInconsistentStore), name)
+	}
+}
+
+func TestStoreFingerprint(t *testing.T) {
+	ep := paths.NewEncrypted
+	up := paths.NewUnencrypted
+
+	type entry struct {
+		bucket string
+		unenc  string
+		enc    string
+		key    string
+		cipher storj.CipherSuite
+	}
+	entries := []entry{
+		{"b1", "u1/u2/u3", "e1/e2/e3", "k3", storj.EncAESGCM},
+		{"b1", "u1/u2/u3/u4", "e1/e2/e3/e4", "k4", storj.EncAESGCM},
+		{"b1", "u1/u5", "e1/e5", "k5", storj.EncSecretBox},
+		{"b2", "u1", "u1", "k1", storj.EncNull},
+	}
+
+	build := func(order []int) *Store {
+		s := NewStore()
+		defaultKey := toKey("default")
+		s.SetDefaultKey(&defaultKey)
+		for _, i := range order {
+			e := entries[i]
+			abortIfError(s.AddWithCipher(e.bucket, up(e.unenc), ep(e.enc), toKey(e.key), e.cipher))
+		}
+		return s
+	}
+
+	fingerprint := func(s *Store) []byte {
+		fp, err := s.Fingerprint()
+		require.NoError(t, err)
+		return fp
+	}
+
+	expected := fingerprint(build([]int{0, 1, 2, 3}))
+	require.Len(t, expected, 32)
+	assert.Equal(t, expected, fingerprint(build([]int{3, 2, 1, 0})))
+	assert.Equal(t, expected, fingerprint(build([]int{2, 0, 3, 1})))
+	assert.Equal(t, expected, fingerprint(build([]int{0, 1, 2, 3}).clone()))
+
+	// any change to the contents changes the fingerprint
+	different := []*Store{build([]int{0, 1, 2}), NewStore()}
+
+	s := build([]int{0, 1, 2, 3})
+	s.SetDefaultKey(nil)
+	different = append(different, s)
+
+	s = build([]int{0, 1, 2, 3})
+	s.SetDefaultPathCipher(storj.EncAESGCM)
+	different = append(different, s)
+
+	s = build([]int{0, 1, 2, 3})
+	bucketKey := toKey("bucket")
+	s.SetBucketDefaultKey("b1", &bucketKey)
+	different = append(different, s)
+
+	s = build([]int{0, 1, 2})
+	abortIfError(s.AddWithCipher("b2", up("u1"), ep("u1"), toKey("k1"), storj.EncNullBase64URL))
+	different = append(different, s)
+
+	for i, s := range different {
+		assert.NotEqual(t, expected, fingerprint(s), i)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package encryption
+
+import (
+	"io"
+)
+
+// StripeReader splits the data read from a reader into fixed size stripes.
+type StripeReader struct {
+	r      io.Reader
+	buf    []byte
+	filled int
+	done   bool
+}
+
+// NewStripeReader returns a StripeReader that reads stripes of stripeSize
+// bytes from r.
+func NewStripeReader(r io.Reader, stripeSize int) *StripeReader {
+	if stripeSize <= 0 {
+		return &StripeReader{r: r}
+	}
+	return &StripeReader{
+		r:   r,
+		buf: make([]byte, stripeSize),
+	}
+}
+
+// Next returns the next stripe. The final stripe is padded with zeros when r
+// doesn't fill it, Filled reports how many bytes of it came from r. Next
+// returns io.EOF when there are no more stripes.
+//
+// The returned slice is only valid until the next call to Next.
+func (s *StripeReader) Next() ([]byte, error) {
+	if len(s.buf) == 0 {
+		return nil, Error.New("invalid stripe size")
+	}
+	if s.done {
+		s.filled = 0
+		return nil, io.EOF
+	}
+
+	n, err := io.ReadFull(s.r, s.buf)
+	s.filled = n
+	switch err {
+	case nil:
+	case io.EOF:
+		s.done = true
+		return nil, io.EOF
+	case io.ErrUnexpectedEOF:
+		s.done = true
+		for i := n; i < len(s.buf); i++ {
+			s.buf[i] = 0
+		}
+	default:
+		return nil, err
+	}
+	return s.buf, nil
+}
+
+// Filled returns the number of bytes read from r in the last stripe
+// returned by Next.
+func (s *StripeReader) Filled() int { return s.filled }
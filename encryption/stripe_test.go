@@ -0,0 +1,75 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package encryption
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testrand"
+)
+
+func TestStripeReader(t *testing.T) {
+	for _, tt := range []struct {
+		dataSize, stripeSize int
+		stripes, lastFilled  int
+	}{
+		{0, 4, 0, 0},
+		{1, 4, 1, 1},
+		{4, 4, 1, 4},
+		{10, 4, 3, 2},
+		{12, 4, 3, 4},
+		{1000, 64, 16, 40},
+	} {
+		data := testrand.BytesInt(tt.dataSize)
+		// one byte reads ensure a stripe is filled from several reads
+		stripes := NewStripeReader(iotest.OneByteReader(bytes.NewReader(data)), tt.stripeSize)
+
+		var read []byte
+		count := 0
+		for {
+			stripe, err := stripes.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			require.Len(t, stripe, tt.stripeSize)
+			count++
+
+			filled := stripes.Filled()
+			if count < tt.stripes {
+				assert.Equal(t, tt.stripeSize, filled)
+			} else {
+				assert.Equal(t, tt.lastFilled, filled)
+			}
+			assert.Equal(t, make([]byte, tt.stripeSize-filled), stripe[filled:], "padding")
+			read = append(read, stripe[:filled]...)
+		}
+
+		assert.Equal(t, tt.stripes, count, tt)
+		assert.True(t, bytes.Equal(data, read), tt)
+
+		_, err := stripes.Next()
+		assert.Equal(t, io.EOF, err)
+	}
+}
+
+func TestStripeReader_Errors(t *testing.T) {
+	_, err := NewStripeReader(bytes.NewReader([]byte{1}), 0).Next()
+	require.Error(t, err)
+
+	failure := errors.New("read failed")
+	_, err = NewStripeReader(io.MultiReader(bytes.NewReader([]byte{1}), &failingReader{failure}), 8).Next()
+	require.Equal(t, failure, err)
+}
+
+type failingReader struct{ err error }
+
+func (r *failingReader) Read([]byte) (int, error) { return 0, r.err }
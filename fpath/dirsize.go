@@ -0,0 +1,29 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package fpath
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+)
+
+// DirSize returns the total size of the regular files in the directory tree
+// at root. Symbolic links are not followed. When an error occurs, or ctx is
+// canceled, it returns the size summed so far along with the error.
+func DirSize(ctx context.Context, root string) (size int64, err error) {
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
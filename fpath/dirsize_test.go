@@ -0,0 +1,49 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package fpath
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirSize(t *testing.T) {
+	err := WithTempDir("fpath-dirsize", func(dir string) error {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "a", "b"), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "one"), make([]byte, 100), 0644))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a", "two"), make([]byte, 20), 0644))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a", "b", "three"), make([]byte, 3), 0644))
+
+		if runtime.GOOS != "windows" {
+			// links are not followed, so the cycle and the linked file are not counted
+			require.NoError(t, os.Symlink(dir, filepath.Join(dir, "a", "b", "cycle")))
+			require.NoError(t, os.Symlink(filepath.Join(dir, "one"), filepath.Join(dir, "link")))
+		}
+
+		ctx := context.Background()
+		size, err := DirSize(ctx, dir)
+		require.NoError(t, err)
+		require.Equal(t, int64(123), size)
+
+		size, err = DirSize(ctx, filepath.Join(dir, "a"))
+		require.NoError(t, err)
+		require.Equal(t, int64(23), size)
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = DirSize(canceled, dir)
+		require.Equal(t, context.Canceled, err)
+
+		_, err = DirSize(ctx, filepath.Join(dir, "missing"))
+		require.True(t, os.IsNotExist(err))
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,16 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package fpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplicationDirDarwin(t *testing.T) {
+	withEnv(t, map[string]string{"HOME": "/Users/user", "XDG_DATA_HOME": "/xdg/data"}, func() {
+		assert.Equal(t, "/Users/user/Library/Application Support/Storj/Uplink", ApplicationDir("storj", "uplink"))
+	})
+}
@@ -0,0 +1,25 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package fpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplicationDirLinux(t *testing.T) {
+	withEnv(t, map[string]string{"XDG_DATA_HOME": "/xdg/data", "HOME": "/home/user"}, func() {
+		assert.Equal(t, "/xdg/data", ApplicationDir())
+		assert.Equal(t, "/xdg/data/storj/uplink", ApplicationDir("Storj", "Uplink"))
+	})
+
+	withEnv(t, map[string]string{"XDG_DATA_HOME": "", "HOME": "/home/user"}, func() {
+		assert.Equal(t, "/home/user/.local/share/storj", ApplicationDir("storj"))
+	})
+
+	withEnv(t, map[string]string{"XDG_DATA_HOME": "", "HOME": ""}, func() {
+		assert.Equal(t, "storj", ApplicationDir("storj"))
+	})
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package fpath
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// withEnv runs fn with the environment variables set to the given values,
+// where the empty value unsets the variable, and restores them afterwards.
+func withEnv(t *testing.T, env map[string]string, fn func()) {
+	for key, value := range env {
+		original, ok := os.LookupEnv(key)
+		defer func(key string) {
+			if ok {
+				require.NoError(t, os.Setenv(key, original))
+			} else {
+				require.NoError(t, os.Unsetenv(key))
+			}
+		}(key)
+
+		if value == "" {
+			require.NoError(t, os.Unsetenv(key))
+		} else {
+			require.NoError(t, os.Setenv(key, value))
+		}
+	}
+	fn()
+}
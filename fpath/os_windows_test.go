@@ -0,0 +1,20 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package fpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplicationDirWindows(t *testing.T) {
+	withEnv(t, map[string]string{"AppData": `C:\Users\user\AppData\Roaming`, "UserProfile": `C:\Users\user`}, func() {
+		assert.Equal(t, `C:\Users\user\AppData\Roaming\Storj\Uplink`, ApplicationDir("storj", "uplink"))
+	})
+
+	withEnv(t, map[string]string{"AppData": "", "AppDataLocal": "", "UserProfile": `C:\Users\user`}, func() {
+		assert.Equal(t, `C:\Users\user\Storj`, ApplicationDir("storj"))
+	})
+}
@@ -0,0 +1,31 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package fpath
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/zeebo/errs"
+)
+
+// WithTempDir creates a temporary directory with the given prefix, calls fn
+// with its path and removes the directory afterwards. The directory is removed
+// even when fn panics, in which case the panic is propagated after cleanup.
+func WithTempDir(prefix string, fn func(dir string) error) (err error) {
+	dir, err := ioutil.TempDir("", prefix)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	defer func() {
+		removeErr := os.RemoveAll(dir)
+		if r := recover(); r != nil {
+			panic(r)
+		}
+		err = errs.Combine(err, errs.Wrap(removeErr))
+	}()
+
+	return fn(dir)
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package fpath
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTempDir(t *testing.T) {
+	var tempdir string
+	err := WithTempDir("fpath-test", func(dir string) error {
+		tempdir = dir
+		info, err := os.Stat(dir)
+		require.NoError(t, err)
+		assert.True(t, info.IsDir())
+		return nil
+	})
+	require.NoError(t, err)
+	_, err = os.Stat(tempdir)
+	assert.True(t, os.IsNotExist(err))
+
+	errFailed := errors.New("failed")
+	err = WithTempDir("fpath-test", func(dir string) error {
+		tempdir = dir
+		return errFailed
+	})
+	assert.True(t, errors.Is(err, errFailed))
+	_, err = os.Stat(tempdir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestWithTempDir_Panic(t *testing.T) {
+	var tempdir string
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = WithTempDir("fpath-test", func(dir string) error {
+			tempdir = dir
+			panic("boom")
+		})
+	})
+	require.NotEmpty(t, tempdir)
+	_, err := os.Stat(tempdir)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,30 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package identity
+
+import (
+	"storj.io/common/storj"
+)
+
+// Version is the identity version number embedded in a certificate authority.
+type Version = storj.IDVersionNumber
+
+const (
+	// V0 is the identity version of identities created before versioning.
+	V0 = Version(storj.V0)
+)
+
+// Version looks up the version number based on the CA certificate's ID version extension.
+func (pi *PeerIdentity) Version() (Version, error) {
+	version, err := storj.IDVersionFromCert(pi.CA)
+	if err != nil {
+		return 0, Error.Wrap(err)
+	}
+	return version.Number, nil
+}
+
+// IsVersionSupported returns whether v is within the inclusive range [min, max].
+func IsVersionSupported(v Version, min, max Version) bool {
+	return min <= v && v <= max
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package identity_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/identity"
+	"storj.io/common/identity/testidentity"
+	"storj.io/common/storj"
+)
+
+func TestPeerIdentity_Version(t *testing.T) {
+	testidentity.IdentityVersionsTest(t, func(t *testing.T, version storj.IDVersion, ident *identity.FullIdentity) {
+		peerVersion, err := ident.PeerIdentity().Version()
+		require.NoError(t, err)
+		assert.Equal(t, version.Number, peerVersion)
+		assert.Equal(t, identity.V0, peerVersion)
+	})
+}
+
+func TestIsVersionSupported(t *testing.T) {
+	for _, tt := range []struct {
+		v, min, max identity.Version
+		supported   bool
+	}{
+		{v: 0, min: 0, max: 0, supported: true},
+		{v: 1, min: 0, max: 2, supported: true},
+		{v: 0, min: 0, max: 2, supported: true},
+		{v: 2, min: 0, max: 2, supported: true},
+		{v: 3, min: 0, max: 2, supported: false},
+		{v: 0, min: 1, max: 2, supported: false},
+		{v: 1, min: 2, max: 1, supported: false},
+	} {
+		assert.Equal(t, tt.supported, identity.IsVersionSupported(tt.v, tt.min, tt.max), "%+v", tt)
+	}
+}
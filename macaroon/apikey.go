@@ -61,20 +61,37 @@ type Action struct {
 	Bucket        []byte
 	EncryptedPath []byte
 	Time          time.Time
+
+	// ObjectSize is the declared size of the object of a write, checked
+	// against the caveat's MaxObjectSize.
+	ObjectSize int64
+	// TotalSize is the total size uploaded with the key including the
+	// write, as tracked by the caller, checked against the caveat's
+	// MaxTotalSize.
+	TotalSize int64
 }
 
+// apiKeyVersion is the version byte of the base58 check encoding used by
+// Serialize. Keys serialized before versioning was introduced use the same
+// byte, so they are parsed as version 0.
+const apiKeyVersion byte = 0
+
 // APIKey implements a Macaroon-backed Storj-v3 API key.
 type APIKey struct {
 	mac *Macaroon
 }
 
 // ParseAPIKey parses a given api key string and returns an APIKey if the
-// APIKey was correctly formatted. It does not validate the key.
+// APIKey was correctly formatted. It does not validate the key. Keys with an
+// unknown version are rejected.
 func ParseAPIKey(key string) (*APIKey, error) {
 	data, version, err := base58.CheckDecode(key)
-	if err != nil || version != 0 {
+	if err != nil {
 		return nil, ErrFormat.New("invalid api key format")
 	}
+	if version != apiKeyVersion {
+		return nil, ErrFormat.New("unsupported api key version %d", version)
+	}
 	mac, err := ParseMacaroon(data)
 	if err != nil {
 		return nil, ErrFormat.Wrap(err)
@@ -210,6 +227,21 @@ func (a *APIKey) Restrict(caveat Caveat) (*APIKey, error) {
 	return &APIKey{mac: mac}, nil
 }
 
+// IsUnrestricted reports whether the APIKey has no caveats at all, which is
+// the case for the root key created by NewAPIKey. Any caveat makes the key
+// restricted, even one that does not disallow anything. An error is returned
+// if a caveat cannot be parsed.
+func (a *APIKey) IsUnrestricted() (bool, error) {
+	caveats := a.mac.Caveats()
+	for _, cavbuf := range caveats {
+		var cav Caveat
+		if err := pb.Unmarshal(cavbuf, &cav); err != nil {
+			return false, ErrFormat.New("invalid caveat format")
+		}
+	}
+	return len(caveats) == 0, nil
+}
+
 // Head returns the identifier for this macaroon's root ancestor.
 func (a *APIKey) Head() []byte {
 	return a.mac.Head()
@@ -220,9 +252,10 @@ func (a *APIKey) Tail() []byte {
 	return a.mac.Tail()
 }
 
-// Serialize serializes the API Key to a string.
+// Serialize serializes the API Key to a string. The string carries the
+// version checked by ParseAPIKey.
 func (a *APIKey) Serialize() string {
-	return base58.CheckEncode(a.mac.Serialize(), 0)
+	return base58.CheckEncode(a.mac.Serialize(), apiKeyVersion)
 }
 
 // SerializeRaw serialize the API Key to raw bytes.
@@ -271,6 +304,12 @@ func (c *Caveat) Allows(action Action) bool {
 		if c.DisallowWrites {
 			return false
 		}
+		if c.MaxObjectSize > 0 && action.ObjectSize > c.MaxObjectSize {
+			return false
+		}
+		if c.MaxTotalSize > 0 && action.TotalSize > c.MaxTotalSize {
+			return false
+		}
 	case ActionList:
 		if c.DisallowLists {
 			return false
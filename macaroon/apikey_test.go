@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcutil/base58"
 	"github.com/stretchr/testify/require"
 	"github.com/zeebo/errs"
 
@@ -65,6 +66,122 @@ func TestSerializeParseRestrictAndCheck(t *testing.T) {
 	require.True(t, ErrUnauthorized.Has(err), err)
 }
 
+func TestParseAPIKey_Version(t *testing.T) {
+	ctx := context.Background()
+
+	// serialized unrestricted key for secret "secret" from before versioning
+	const v0 = "13YqdNnpV5LyU2M9dZgcuxJvgDYT5yVnKgUdan2CQ1BzMDhAcC7wjDSqKGK8TomjD6EuyhEXRn8qSPfMYdyhFQNjMuzDDQ7RDNrTTdk"
+
+	key, err := ParseAPIKey(v0)
+	require.NoError(t, err)
+	require.Equal(t, v0, key.Serialize())
+	require.NoError(t, key.Check(ctx, []byte("secret"), Action{Op: ActionRead, Time: time.Now()}, nil))
+
+	future := base58.CheckEncode(key.SerializeRaw(), apiKeyVersion+1)
+	_, err = ParseAPIKey(future)
+	require.True(t, ErrFormat.Has(err), err)
+	require.Contains(t, err.Error(), "unsupported api key version 1")
+
+	_, err = ParseAPIKey(v0[:len(v0)-1])
+	require.True(t, ErrFormat.Has(err), err)
+}
+
+func TestSizeLimits(t *testing.T) {
+	ctx := context.Background()
+
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	key, err := NewAPIKey(secret)
+	require.NoError(t, err)
+
+	restricted, err := key.Restrict(Caveat{MaxObjectSize: 1000, MaxTotalSize: 5000})
+	require.NoError(t, err)
+
+	parsed, err := ParseAPIKey(restricted.Serialize())
+	require.NoError(t, err)
+
+	put := func(objectSize, totalSize int64) Action {
+		return Action{Op: ActionWrite, Time: time.Now(), ObjectSize: objectSize, TotalSize: totalSize}
+	}
+
+	require.NoError(t, parsed.Check(ctx, secret, put(1000, 1000), nil))
+	require.NoError(t, parsed.Check(ctx, secret, put(10, 5000), nil))
+
+	err = parsed.Check(ctx, secret, put(1001, 1001), nil)
+	require.True(t, ErrUnauthorized.Has(err), err)
+	err = parsed.Check(ctx, secret, put(10, 5001), nil)
+	require.True(t, ErrUnauthorized.Has(err), err)
+
+	// the limits only apply to writes
+	require.NoError(t, parsed.Check(ctx, secret, Action{Op: ActionRead, Time: time.Now(), EncryptedPath: []byte("a"), ObjectSize: 10000}, nil))
+
+	// without limits any size is allowed
+	require.NoError(t, key.Check(ctx, secret, put(1<<40, 1<<50), nil))
+}
+
+func TestIsUnrestricted(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	key, err := NewAPIKey(secret)
+	require.NoError(t, err)
+
+	unrestricted, err := key.IsUnrestricted()
+	require.NoError(t, err)
+	require.True(t, unrestricted)
+
+	parsedKey, err := ParseAPIKey(key.Serialize())
+	require.NoError(t, err)
+	unrestricted, err = parsedKey.IsUnrestricted()
+	require.NoError(t, err)
+	require.True(t, unrestricted)
+
+	for _, caveat := range []Caveat{
+		{},
+		{DisallowReads: true},
+		{AllowedPaths: []*Caveat_Path{{Bucket: []byte("bucket")}}},
+	} {
+		restricted, err := key.Restrict(caveat)
+		require.NoError(t, err)
+
+		unrestricted, err := restricted.IsUnrestricted()
+		require.NoError(t, err)
+		require.False(t, unrestricted)
+	}
+
+	mac, err := key.mac.AddFirstPartyCaveat([]byte{0xff})
+	require.NoError(t, err)
+	_, err = (&APIKey{mac: mac}).IsUnrestricted()
+	require.Error(t, err)
+	require.True(t, ErrFormat.Has(err))
+}
+
+func TestHeadAndTail(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	key, err := NewAPIKey(secret)
+	require.NoError(t, err)
+
+	readOnly, err := key.Restrict(Caveat{DisallowWrites: true, DisallowDeletes: true})
+	require.NoError(t, err)
+	bucketOnly, err := key.Restrict(Caveat{AllowedPaths: []*Caveat_Path{{Bucket: []byte("bucket")}}})
+	require.NoError(t, err)
+	nested, err := readOnly.Restrict(Caveat{DisallowLists: true})
+	require.NoError(t, err)
+
+	for _, attenuated := range []*APIKey{readOnly, bucketOnly, nested} {
+		require.Equal(t, key.Head(), attenuated.Head())
+		require.NotEqual(t, key.Tail(), attenuated.Tail())
+	}
+	require.NotEqual(t, readOnly.Tail(), bucketOnly.Tail())
+	require.NotEqual(t, readOnly.Tail(), nested.Tail())
+
+	// the head and tail survive serialization
+	parsed, err := ParseAPIKey(nested.Serialize())
+	require.NoError(t, err)
+	require.Equal(t, nested.Head(), parsed.Head())
+	require.Equal(t, nested.Tail(), parsed.Tail())
+}
+
 func TestRevocation(t *testing.T) {
 	ctx := testcontext.New(t)
 
@@ -4,7 +4,9 @@
 package macaroon
 
 import (
+	"bytes"
 	"crypto/rand"
+	"time"
 )
 
 // NewCaveat returns a Caveat with a random generated nonce.
@@ -13,3 +15,134 @@ func NewCaveat() (Caveat, error) {
 	_, err := rand.Read(buf[:])
 	return Caveat{Nonce: buf[:]}, err
 }
+
+// IntersectCaveats returns a caveat that allows only the actions allowed by
+// both a and b. The disallowed operations are combined, the validity window is
+// narrowed, the stricter size limits are kept and the allowed paths are
+// intersected.
+//
+// If a bucket is allowed by both caveats but with non-overlapping path
+// prefixes, and other paths do overlap, the bucket is dropped entirely. The
+// result is then stricter than applying both caveats for reads of that
+// bucket's metadata. The returned caveat has no nonce.
+func IntersectCaveats(a, b Caveat) Caveat {
+	result := Caveat{
+		DisallowReads:   a.DisallowReads || b.DisallowReads,
+		DisallowWrites:  a.DisallowWrites || b.DisallowWrites,
+		DisallowLists:   a.DisallowLists || b.DisallowLists,
+		DisallowDeletes: a.DisallowDeletes || b.DisallowDeletes,
+		MaxObjectSize:   minLimit(a.MaxObjectSize, b.MaxObjectSize),
+		MaxTotalSize:    minLimit(a.MaxTotalSize, b.MaxTotalSize),
+	}
+
+	switch {
+	case a.NotAfter == nil:
+		result.NotAfter = copyTime(b.NotAfter)
+	case b.NotAfter == nil || a.NotAfter.Before(*b.NotAfter):
+		result.NotAfter = copyTime(a.NotAfter)
+	default:
+		result.NotAfter = copyTime(b.NotAfter)
+	}
+
+	switch {
+	case a.NotBefore == nil:
+		result.NotBefore = copyTime(b.NotBefore)
+	case b.NotBefore == nil || a.NotBefore.After(*b.NotBefore):
+		result.NotBefore = copyTime(a.NotBefore)
+	default:
+		result.NotBefore = copyTime(b.NotBefore)
+	}
+
+	switch {
+	case len(a.AllowedPaths) == 0:
+		result.AllowedPaths = copyPaths(b.AllowedPaths)
+	case len(b.AllowedPaths) == 0:
+		result.AllowedPaths = copyPaths(a.AllowedPaths)
+	default:
+		result.AllowedPaths = intersectPaths(a.AllowedPaths, b.AllowedPaths)
+		if len(result.AllowedPaths) == 0 {
+			// no object is allowed by both caveats, but the metadata of the
+			// buckets they share can still be read.
+			result.DisallowReads = true
+			result.DisallowWrites = true
+			result.DisallowLists = true
+			result.DisallowDeletes = true
+			result.AllowedPaths = commonBuckets(a.AllowedPaths, b.AllowedPaths)
+		}
+	}
+
+	return result
+}
+
+// intersectPaths returns the paths allowed by both a and b.
+func intersectPaths(a, b []*Caveat_Path) (paths []*Caveat_Path) {
+	for _, pa := range a {
+		for _, pb := range b {
+			if !bytes.Equal(pa.Bucket, pb.Bucket) {
+				continue
+			}
+			switch {
+			case bytes.HasPrefix(pa.EncryptedPathPrefix, pb.EncryptedPathPrefix):
+				paths = appendPath(paths, pa)
+			case bytes.HasPrefix(pb.EncryptedPathPrefix, pa.EncryptedPathPrefix):
+				paths = appendPath(paths, pb)
+			}
+		}
+	}
+	return paths
+}
+
+// commonBuckets returns a path for every bucket present in both a and b. When
+// there are no such buckets, it returns a path that matches no bucket.
+func commonBuckets(a, b []*Caveat_Path) (paths []*Caveat_Path) {
+	for _, pa := range a {
+		for _, pb := range b {
+			if bytes.Equal(pa.Bucket, pb.Bucket) {
+				paths = appendPath(paths, &Caveat_Path{Bucket: pa.Bucket})
+			}
+		}
+	}
+	if len(paths) == 0 {
+		paths = append(paths, &Caveat_Path{})
+	}
+	return paths
+}
+
+// appendPath appends a copy of path unless an equal path is already present.
+func appendPath(paths []*Caveat_Path, path *Caveat_Path) []*Caveat_Path {
+	for _, existing := range paths {
+		if bytes.Equal(existing.Bucket, path.Bucket) &&
+			bytes.Equal(existing.EncryptedPathPrefix, path.EncryptedPathPrefix) {
+			return paths
+		}
+	}
+	return append(paths, &Caveat_Path{
+		Bucket:              append([]byte(nil), path.Bucket...),
+		EncryptedPathPrefix: append([]byte(nil), path.EncryptedPathPrefix...),
+	})
+}
+
+// copyPaths returns a deep copy of paths.
+func copyPaths(paths []*Caveat_Path) (copied []*Caveat_Path) {
+	for _, path := range paths {
+		copied = appendPath(copied, path)
+	}
+	return copied
+}
+
+// copyTime returns a copy of t.
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
+
+// minLimit returns the stricter of two size limits, where zero means unlimited.
+func minLimit(a, b int64) int64 {
+	if a <= 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
+}
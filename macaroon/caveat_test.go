@@ -0,0 +1,133 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package macaroon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testActions returns actions covering every operation on a few buckets and paths.
+func testActions(now time.Time) (actions []Action) {
+	for _, ts := range []time.Time{now.Add(-2 * time.Hour), now, now.Add(2 * time.Hour)} {
+		for _, op := range []ActionType{ActionRead, ActionWrite, ActionList, ActionDelete, ActionProjectInfo} {
+			for _, bucket := range []string{"", "bucket1", "bucket2"} {
+				for _, path := range []string{"", "a", "a/b", "a/b/c", "x"} {
+					actions = append(actions, Action{
+						Op:            op,
+						Time:          ts,
+						Bucket:        []byte(bucket),
+						EncryptedPath: []byte(path),
+					})
+				}
+			}
+		}
+	}
+
+	for _, size := range []int64{0, 100, 1000, 10000} {
+		actions = append(actions,
+			Action{Op: ActionWrite, Time: now, Bucket: []byte("bucket1"), EncryptedPath: []byte("a"), ObjectSize: size, TotalSize: size},
+			Action{Op: ActionWrite, Time: now, Bucket: []byte("bucket1"), EncryptedPath: []byte("a"), ObjectSize: size, TotalSize: 5000},
+		)
+	}
+	return actions
+}
+
+func TestIntersectCaveats(t *testing.T) {
+	now := time.Now()
+	hourAgo, inHour := now.Add(-time.Hour), now.Add(time.Hour)
+
+	readOnly := Caveat{DisallowWrites: true, DisallowLists: true, DisallowDeletes: true}
+	bucketScoped := Caveat{AllowedPaths: []*Caveat_Path{{Bucket: []byte("bucket1")}}}
+
+	for i, tt := range []struct {
+		a, b Caveat
+	}{
+		{readOnly, bucketScoped},
+		{readOnly, Caveat{}},
+		{Caveat{NotAfter: &inHour}, Caveat{NotBefore: &hourAgo}},
+		{Caveat{NotAfter: &inHour, NotBefore: &now}, Caveat{NotAfter: &now, NotBefore: &hourAgo}},
+		{bucketScoped, Caveat{AllowedPaths: []*Caveat_Path{
+			{Bucket: []byte("bucket1"), EncryptedPathPrefix: []byte("a/b")},
+			{Bucket: []byte("bucket2")},
+		}}},
+		{
+			Caveat{AllowedPaths: []*Caveat_Path{{Bucket: []byte("bucket1"), EncryptedPathPrefix: []byte("a")}}},
+			Caveat{AllowedPaths: []*Caveat_Path{{Bucket: []byte("bucket1"), EncryptedPathPrefix: []byte("x")}}},
+		},
+		{
+			Caveat{AllowedPaths: []*Caveat_Path{{Bucket: []byte("bucket1")}}},
+			Caveat{AllowedPaths: []*Caveat_Path{{Bucket: []byte("bucket2")}}},
+		},
+		{Caveat{MaxObjectSize: 1000}, Caveat{MaxObjectSize: 100, MaxTotalSize: 8000}},
+		{Caveat{MaxObjectSize: 1000, MaxTotalSize: 2000}, Caveat{}},
+		{Caveat{MaxTotalSize: 1000}, Caveat{MaxTotalSize: 10000}},
+	} {
+		intersected := IntersectCaveats(tt.a, tt.b)
+		for _, action := range testActions(now) {
+			tag := fmt.Sprintf("#%d: %+v", i, action)
+			expected := tt.a.Allows(action) && tt.b.Allows(action)
+			assert.Equal(t, expected, intersected.Allows(action), tag)
+		}
+	}
+}
+
+func TestIntersectCaveats_Disjoint(t *testing.T) {
+	now := time.Now()
+
+	a := Caveat{AllowedPaths: []*Caveat_Path{
+		{Bucket: []byte("bucket1"), EncryptedPathPrefix: []byte("a")},
+		{Bucket: []byte("bucket2"), EncryptedPathPrefix: []byte("a")},
+	}}
+	b := Caveat{AllowedPaths: []*Caveat_Path{
+		{Bucket: []byte("bucket1"), EncryptedPathPrefix: []byte("a/b")},
+		{Bucket: []byte("bucket2"), EncryptedPathPrefix: []byte("x")},
+	}}
+
+	// the result is never looser than applying both caveats
+	intersected := IntersectCaveats(a, b)
+	for _, action := range testActions(now) {
+		if intersected.Allows(action) {
+			assert.True(t, a.Allows(action) && b.Allows(action), fmt.Sprintf("%+v", action))
+		}
+	}
+
+	assert.True(t, intersected.Allows(Action{
+		Op: ActionRead, Time: now, Bucket: []byte("bucket1"), EncryptedPath: []byte("a/b/c"),
+	}))
+	assert.False(t, intersected.Allows(Action{
+		Op: ActionRead, Time: now, Bucket: []byte("bucket2"), EncryptedPath: []byte("x"),
+	}))
+}
+
+func TestIntersectCaveats_Check(t *testing.T) {
+	ctx := context.Background()
+
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	key, err := NewAPIKey(secret)
+	require.NoError(t, err)
+
+	readOnly := Caveat{DisallowWrites: true, DisallowLists: true, DisallowDeletes: true}
+	bucketScoped := Caveat{AllowedPaths: []*Caveat_Path{{Bucket: []byte("bucket1")}}}
+
+	both, err := key.Restrict(readOnly)
+	require.NoError(t, err)
+	both, err = both.Restrict(bucketScoped)
+	require.NoError(t, err)
+
+	intersected, err := key.Restrict(IntersectCaveats(readOnly, bucketScoped))
+	require.NoError(t, err)
+
+	for _, action := range testActions(time.Now()) {
+		tag := fmt.Sprintf("%+v", action)
+		expected := both.Check(ctx, secret, action, nil) == nil
+		assert.Equal(t, expected, intersected.Check(ctx, secret, action, nil) == nil, tag)
+	}
+}
@@ -6,6 +6,7 @@ package memory
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
 )
@@ -219,6 +220,60 @@ func (size *Size) Set(s string) error {
 	return nil
 }
 
+// strictUnits contains the units accepted by ParseStrict.
+var strictUnits = map[string]Size{
+	"B": B,
+
+	"KB": KB, "kB": KB,
+	"MB": MB,
+	"GB": GB,
+	"TB": TB,
+	"PB": PB,
+	"EB": EB,
+
+	"KiB": KiB,
+	"MiB": MiB,
+	"GiB": GiB,
+	"TiB": TiB,
+	"PiB": PiB,
+	"EiB": EiB,
+}
+
+// ParseStrict parses a size where the unit must be given exactly. SI units
+// (KB, MB, GB, ...) are powers of 1000 and IEC units (KiB, MiB, GiB, ...) are
+// powers of 1024. Unlike Set, units are case sensitive, a missing unit or a
+// unit without B is an error, and the value must be a whole number of bytes.
+func ParseStrict(s string) (Size, error) {
+	p := len(s)
+	for p > 0 && isLetter(s[p-1]) {
+		p--
+	}
+
+	value, suffix := strings.TrimSpace(s[:p]), s[p:]
+	if value == "" {
+		return 0, fmt.Errorf("missing value in %q", s)
+	}
+
+	unit, ok := strictUnits[suffix]
+	if !ok {
+		return 0, fmt.Errorf("unknown or ambiguous unit %q", suffix)
+	}
+
+	v, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	v.Mul(v, new(big.Rat).SetInt64(unit.Int64()))
+	if !v.IsInt() {
+		return 0, fmt.Errorf("%q is not a whole number of bytes", s)
+	}
+	if !v.Num().IsInt64() {
+		return 0, fmt.Errorf("%q is out of range", s)
+	}
+
+	return Size(v.Num().Int64()), nil
+}
+
 // Type implements pflag.Value.
 func (Size) Type() string { return "memory.Size" }
 
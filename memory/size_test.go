@@ -187,6 +187,52 @@ func TestInvalidParse(t *testing.T) {
 	}
 }
 
+func TestParseStrict(t *testing.T) {
+	var tests = []struct {
+		size memory.Size
+		text string
+	}{
+		{1_000_000_000, "1GB"},
+		{1 << 30, "1GiB"},
+		{1, "1B"},
+		{1 * kb, "1KB"},
+		{1 * kb, "1kB"},
+		{1 * kib, "1KiB"},
+		{1536, "1.5KiB"},
+		{1500 * mb, "1.5 GB"},
+		{2 * tb, "2TB"},
+		{2 * tib, "2TiB"},
+		{3 * pib, "3PiB"},
+		{1 * eb, "1EB"},
+		{-1 * mib, "-1MiB"},
+	}
+
+	for _, test := range tests {
+		size, err := memory.ParseStrict(test.text)
+		require.NoError(t, err, test.text)
+		require.Equal(t, test.size, size, test.text)
+	}
+
+	for _, text := range []string{
+		"",
+		"1",
+		"GB",
+		"1G",
+		"1k",
+		"1gb",
+		"1Gb",
+		"1gib",
+		"1GIB",
+		"1QB",
+		"x1GB",
+		"0.1B",
+		"10EiB",
+	} {
+		_, err := memory.ParseStrict(text)
+		require.Error(t, err, text)
+	}
+}
+
 func TestJSON(t *testing.T) {
 	var input struct {
 		Value memory.Size `json:"value"`
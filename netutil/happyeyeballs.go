@@ -0,0 +1,157 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package netutil
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/zeebo/errs"
+)
+
+// HappyEyeballsDelay is the head start IPv6 connections get before IPv4
+// connections are attempted, see RFC 8305.
+const HappyEyeballsDelay = 300 * time.Millisecond
+
+// DialHappyEyeballs resolves host and races connections to its IPv6 and IPv4
+// addresses. IPv6 addresses are tried first and IPv4 addresses are tried after
+// HappyEyeballsDelay or as soon as all IPv6 attempts fail. The first successful
+// connection is returned and the other attempts are canceled.
+func DialHappyEyeballs(ctx context.Context, network, host, port string) (_ net.Conn, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var dialer net.Dialer
+	eyeballs := happyEyeballs{
+		lookup: net.DefaultResolver.LookupIPAddr,
+		dial:   dialer.DialContext,
+		delay:  HappyEyeballsDelay,
+	}
+	return eyeballs.Dial(ctx, network, host, port)
+}
+
+// happyEyeballs implements racing connections to IPv6 and IPv4 addresses.
+type happyEyeballs struct {
+	lookup func(ctx context.Context, host string) ([]net.IPAddr, error)
+	dial   func(ctx context.Context, network, address string) (net.Conn, error)
+	delay  time.Duration
+}
+
+// Dial resolves host and races connections to its addresses.
+func (eyeballs *happyEyeballs) Dial(ctx context.Context, network, host, port string) (net.Conn, error) {
+	var addrs []net.IPAddr
+	if ip := net.ParseIP(host); ip != nil {
+		addrs = []net.IPAddr{{IP: ip}}
+	} else {
+		var err error
+		addrs, err = eyeballs.lookup(ctx, host)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+	}
+
+	var ipv6, ipv4 []net.IP
+	for _, addr := range addrs {
+		if addr.IP.To4() != nil {
+			ipv4 = append(ipv4, addr.IP)
+		} else {
+			ipv6 = append(ipv6, addr.IP)
+		}
+	}
+
+	switch network {
+	case "tcp":
+	case "tcp4":
+		ipv6 = nil
+	case "tcp6":
+		ipv4 = nil
+	default:
+		return nil, errs.New("unsupported network %q", network)
+	}
+
+	primary, fallback := ipv6, ipv4
+	if len(primary) == 0 {
+		primary, fallback = fallback, nil
+	}
+	if len(primary) == 0 {
+		return nil, errs.New("no addresses found for %q", host)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	results := make(chan result, 2)
+	pending := 0
+	start := func(ips []net.IP) {
+		pending++
+		go func() {
+			conn, err := eyeballs.dialSerial(ctx, network, ips, port)
+			results <- result{conn: conn, err: err}
+		}()
+	}
+
+	start(primary)
+
+	var headStart <-chan time.Time
+	if len(fallback) > 0 {
+		timer := time.NewTimer(eyeballs.delay)
+		defer timer.Stop()
+		headStart = timer.C
+	}
+
+	var group errs.Group
+	for pending > 0 {
+		select {
+		case <-headStart:
+			headStart = nil
+			start(fallback)
+
+		case res := <-results:
+			pending--
+			if res.err == nil {
+				cancel()
+				// close connections from attempts that still succeed after the cancel.
+				go func(pending int) {
+					for ; pending > 0; pending-- {
+						if late := <-results; late.conn != nil {
+							_ = late.conn.Close()
+						}
+					}
+				}(pending)
+				return res.conn, nil
+			}
+			group.Add(res.err)
+
+			// don't wait for the head start when the primary attempt failed.
+			if headStart != nil {
+				headStart = nil
+				start(fallback)
+			}
+		}
+	}
+
+	return nil, errs.Wrap(group.Err())
+}
+
+// dialSerial tries to connect to the ips one after another and returns the first success.
+func (eyeballs *happyEyeballs) dialSerial(ctx context.Context, network string, ips []net.IP, port string) (net.Conn, error) {
+	var group errs.Group
+	for _, ip := range ips {
+		if err := ctx.Err(); err != nil {
+			group.Add(err)
+			break
+		}
+
+		conn, err := eyeballs.dial(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		group.Add(err)
+	}
+	return nil, group.Err()
+}
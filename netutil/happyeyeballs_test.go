@@ -0,0 +1,131 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package netutil
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubConn is a net.Conn that only knows its remote address.
+type stubConn struct {
+	net.Conn
+	address string
+}
+
+func (conn *stubConn) Close() error { return nil }
+
+// stubNetwork resolves every host to an IPv6 and IPv4 address and dials
+// them with the configured behavior.
+type stubNetwork struct {
+	ipv6Hangs bool
+	ipv4Fails bool
+
+	mu       sync.Mutex
+	canceled []string
+}
+
+func (network *stubNetwork) lookup(ctx context.Context, host string) ([]net.IPAddr, error) {
+	return []net.IPAddr{{IP: net.ParseIP("2001:db8::1")}, {IP: net.ParseIP("192.0.2.1")}}, nil
+}
+
+func (network *stubNetwork) dial(ctx context.Context, _, address string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	ipv4 := net.ParseIP(host).To4() != nil
+
+	if !ipv4 && network.ipv6Hangs {
+		<-ctx.Done()
+		network.mu.Lock()
+		network.canceled = append(network.canceled, address)
+		network.mu.Unlock()
+		return nil, ctx.Err()
+	}
+	if ipv4 && network.ipv4Fails {
+		return nil, errors.New("connection refused")
+	}
+
+	return &stubConn{address: address}, nil
+}
+
+func (network *stubNetwork) Canceled() []string {
+	network.mu.Lock()
+	defer network.mu.Unlock()
+	return append([]string(nil), network.canceled...)
+}
+
+func TestHappyEyeballs_IPv6Hangs(t *testing.T) {
+	network := &stubNetwork{ipv6Hangs: true}
+	eyeballs := happyEyeballs{lookup: network.lookup, dial: network.dial, delay: 50 * time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	start := time.Now()
+	conn, err := eyeballs.Dial(ctx, "tcp", "example.test", "7777")
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.1:7777", conn.(*stubConn).address)
+	assert.True(t, time.Since(start) >= eyeballs.delay)
+	assert.True(t, time.Since(start) < 10*time.Second)
+
+	// the hanging IPv6 attempt is canceled
+	for deadline := time.Now().Add(10 * time.Second); len(network.Canceled()) == 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, []string{"[2001:db8::1]:7777"}, network.Canceled())
+}
+
+func TestHappyEyeballs_PrefersIPv6(t *testing.T) {
+	network := &stubNetwork{}
+	eyeballs := happyEyeballs{lookup: network.lookup, dial: network.dial, delay: time.Minute}
+
+	conn, err := eyeballs.Dial(context.Background(), "tcp", "example.test", "7777")
+	require.NoError(t, err)
+	assert.Equal(t, "[2001:db8::1]:7777", conn.(*stubConn).address)
+
+	// the other family is not dialed during the head start
+	conn, err = eyeballs.Dial(context.Background(), "tcp4", "example.test", "7777")
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.1:7777", conn.(*stubConn).address)
+}
+
+func TestHappyEyeballs_Failures(t *testing.T) {
+	network := &stubNetwork{ipv4Fails: true}
+	eyeballs := happyEyeballs{lookup: network.lookup, dial: network.dial, delay: time.Millisecond}
+
+	_, err := eyeballs.Dial(context.Background(), "tcp4", "example.test", "7777")
+	require.Error(t, err)
+
+	_, err = eyeballs.Dial(context.Background(), "udp", "example.test", "7777")
+	require.Error(t, err)
+}
+
+func TestDialHappyEyeballs(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			_ = conn.Close()
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+
+	conn, err := DialHappyEyeballs(context.Background(), "tcp", host, port)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package netutil
+
+import (
+	"net"
+	"sync/atomic"
+)
+
+// Counters holds the number of bytes read from and written to a connection.
+// It is safe to use concurrently with the connection.
+type Counters struct {
+	read    int64
+	written int64
+}
+
+// Read returns the number of bytes read so far.
+func (counters *Counters) Read() int64 { return atomic.LoadInt64(&counters.read) }
+
+// Written returns the number of bytes written so far.
+func (counters *Counters) Written() int64 { return atomic.LoadInt64(&counters.written) }
+
+// meteredConn wraps a net.Conn and counts the bytes going through it.
+type meteredConn struct {
+	net.Conn
+	counters *Counters
+}
+
+// MeteredConn wraps the conn so that every Read and Write updates the returned
+// counters. Everything else, including deadlines, is passed through unchanged.
+func MeteredConn(conn net.Conn) (net.Conn, *Counters) {
+	counters := new(Counters)
+	return &meteredConn{Conn: conn, counters: counters}, counters
+}
+
+// Read reads from the connection and counts the bytes read.
+func (c *meteredConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	atomic.AddInt64(&c.counters.read, int64(n))
+	return n, err
+}
+
+// Write writes to the connection and counts the bytes written.
+func (c *meteredConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	atomic.AddInt64(&c.counters.written, int64(n))
+	return n, err
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package netutil
+
+import (
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeteredConn(t *testing.T) {
+	client, server := net.Pipe()
+	defer func() { _ = server.Close() }()
+
+	conn, counters := MeteredConn(client)
+	defer func() { _ = conn.Close() }()
+
+	go func() {
+		buf := make([]byte, 100)
+		_, _ = io.ReadFull(server, buf)
+		_, _ = server.Write(make([]byte, 30))
+	}()
+
+	n, err := conn.Write(make([]byte, 60))
+	require.NoError(t, err)
+	assert.Equal(t, 60, n)
+	n, err = conn.Write(make([]byte, 40))
+	require.NoError(t, err)
+	assert.Equal(t, 40, n)
+
+	_, err = io.ReadFull(conn, make([]byte, 30))
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(100), counters.Written())
+	assert.Equal(t, int64(30), counters.Read())
+
+	// deadlines are passed through
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(10*time.Millisecond)))
+	_, err = conn.Read(make([]byte, 1))
+	var netErr net.Error
+	require.True(t, errors.As(err, &netErr) && netErr.Timeout(), err)
+	assert.Equal(t, int64(30), counters.Read())
+}
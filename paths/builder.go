@@ -0,0 +1,52 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package paths
+
+import (
+	"strings"
+
+	"github.com/zeebo/errs"
+)
+
+// ErrComponent is used when a path component can not be appended.
+var ErrComponent = errs.Class("path component")
+
+// Builder constructs a path component by component.
+//
+// The zero value is an empty builder ready to use.
+type Builder struct {
+	components []string
+}
+
+// Append adds a component to the end of the path. Components containing the
+// path separator are rejected and not added.
+func (b *Builder) Append(component string) error {
+	if strings.IndexByte(component, '/') >= 0 {
+		return ErrComponent.New("component %q contains separator", component)
+	}
+	b.components = append(b.components, component)
+	return nil
+}
+
+// AppendAll adds all components to the end of the path. When any of the
+// components is rejected none of them are added.
+func (b *Builder) AppendAll(components []string) error {
+	for _, component := range components {
+		if strings.IndexByte(component, '/') >= 0 {
+			return ErrComponent.New("component %q contains separator", component)
+		}
+	}
+	b.components = append(b.components, components...)
+	return nil
+}
+
+// Build returns the components joined as an Unencrypted path.
+func (b *Builder) Build() Unencrypted {
+	return NewUnencrypted(strings.Join(b.components, "/"))
+}
+
+// BuildEncrypted returns the components joined as an Encrypted path.
+func (b *Builder) BuildEncrypted() Encrypted {
+	return NewEncrypted(strings.Join(b.components, "/"))
+}
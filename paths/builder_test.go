@@ -0,0 +1,48 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package paths
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	var b Builder
+	assert.Equal(t, NewUnencrypted(""), b.Build())
+
+	require.NoError(t, b.Append("a"))
+	require.NoError(t, b.AppendAll([]string{"b", "c"}))
+	assert.Equal(t, NewUnencrypted("a/b/c"), b.Build())
+	assert.Equal(t, NewEncrypted("a/b/c"), b.BuildEncrypted())
+
+	it := b.Build().Iterator()
+	for _, expected := range []string{"a", "b", "c"} {
+		assert.Equal(t, expected, it.Next())
+	}
+	assert.True(t, it.Done())
+
+	// empty components are kept
+	var empty Builder
+	require.NoError(t, empty.AppendAll([]string{"a", "", "b", ""}))
+	assert.Equal(t, NewUnencrypted("a//b/"), empty.Build())
+}
+
+func TestBuilder_Separator(t *testing.T) {
+	var b Builder
+	require.NoError(t, b.Append("a"))
+
+	err := b.Append("b/c")
+	require.Error(t, err)
+	assert.True(t, ErrComponent.Has(err))
+
+	err = b.AppendAll([]string{"d", "e/", "f"})
+	require.Error(t, err)
+	assert.True(t, ErrComponent.Has(err))
+
+	// rejected components are not added
+	assert.Equal(t, NewUnencrypted("a"), b.Build())
+}
@@ -42,11 +42,18 @@ func (path Unencrypted) Raw() string {
 	return path.raw
 }
 
-// String returns a human readable form of the Unencrypted.
+// String returns a human readable form of the Unencrypted. It is the raw
+// path, so UnencryptedFromString is its inverse.
 func (path Unencrypted) String() string {
 	return path.Raw()
 }
 
+// UnencryptedFromString returns the Unencrypted for the raw path s. It is the
+// inverse of Unencrypted.String; the empty string is the empty path.
+func UnencryptedFromString(s string) Unencrypted {
+	return NewUnencrypted(s)
+}
+
 // Consume attempts to remove the prefix from the Unencrypted path and
 // reports a boolean indicating if it was able to do so.
 func (path Unencrypted) Consume(prefix Unencrypted) (Unencrypted, bool) {
@@ -66,6 +73,12 @@ func (path Unencrypted) Less(other Unencrypted) bool {
 	return path.raw < other.raw
 }
 
+// NumComponents returns the number of components the Iterator of the
+// Unencrypted would return, without allocating them.
+func (path Unencrypted) NumComponents() int {
+	return numComponents(path.raw)
+}
+
 //
 // encrypted path
 //
@@ -85,11 +98,18 @@ func (path Encrypted) Raw() string {
 	return path.raw
 }
 
-// String returns a human readable form of the Encrypted.
+// String returns a human readable form of the Encrypted. It is the raw path,
+// so EncryptedFromString is its inverse.
 func (path Encrypted) String() string {
 	return path.Raw()
 }
 
+// EncryptedFromString returns the Encrypted for the raw path s. It is the
+// inverse of Encrypted.String; the empty string is the empty path.
+func EncryptedFromString(s string) Encrypted {
+	return NewEncrypted(s)
+}
+
 // Consume attempts to remove the prefix from the Encrypted path and
 // reports a boolean indicating if it was able to do so.
 func (path Encrypted) Consume(prefix Encrypted) (Encrypted, bool) {
@@ -109,6 +129,28 @@ func (path Encrypted) Less(other Encrypted) bool {
 	return path.raw < other.raw
 }
 
+// NumComponents returns the number of components the Iterator of the
+// Encrypted would return, without allocating them.
+func (path Encrypted) NumComponents() int {
+	return numComponents(path.raw)
+}
+
+// SameShape reports whether unenc and enc have the same number of components,
+// so that enc could be the encryption of unenc. It is the same check that
+// encryption.Store.AddWithCipher enforces when adding a mapping.
+func SameShape(unenc Unencrypted, enc Encrypted) bool {
+	return unenc.NumComponents() == enc.NumComponents()
+}
+
+// numComponents returns the number of components in the raw path. The empty
+// path has no components, otherwise every separator starts a new one.
+func numComponents(raw string) int {
+	if raw == "" {
+		return 0
+	}
+	return strings.Count(raw, "/") + 1
+}
+
 //
 // path component iteration
 //
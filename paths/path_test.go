@@ -72,6 +72,81 @@ func TestEncrypted(t *testing.T) {
 	assert.Equal(t, it.Consumed(), "")
 }
 
+func TestFromString(t *testing.T) {
+	for _, raw := range []string{"", "a", "a/", "/", "a/b/c", "a//b"} {
+		unenc := UnencryptedFromString(raw)
+		assert.Equal(t, raw, unenc.String())
+		assert.Equal(t, NewUnencrypted(raw), unenc)
+		assert.Equal(t, unenc, UnencryptedFromString(unenc.String()))
+		assert.Equal(t, raw != "", unenc.Valid())
+
+		enc := EncryptedFromString(raw)
+		assert.Equal(t, raw, enc.String())
+		assert.Equal(t, NewEncrypted(raw), enc)
+		assert.Equal(t, enc, EncryptedFromString(enc.String()))
+		assert.Equal(t, raw != "", enc.Valid())
+	}
+
+	// the zero values are the empty path
+	assert.Equal(t, Unencrypted{}, UnencryptedFromString(Unencrypted{}.String()))
+	assert.Equal(t, Encrypted{}, EncryptedFromString(Encrypted{}.String()))
+}
+
+func TestSameShape(t *testing.T) {
+	for i, tt := range []struct {
+		unenc string
+		enc   string
+		same  bool
+	}{
+		{"", "", true},
+		{"a", "x", true},
+		{"a/b/c", "x/y/z", true},
+		{"a/", "x/", true},
+		{"/", "//", false},
+		{"", "x", false},
+		{"a", "", false},
+		{"a/b", "x", false},
+		{"a", "x/y", false},
+		{"a/b/", "x/y", false},
+	} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+		assert.Equal(t, tt.same, SameShape(NewUnencrypted(tt.unenc), NewEncrypted(tt.enc)), errTag)
+	}
+}
+
+func TestNumComponents(t *testing.T) {
+	for i, tt := range []struct {
+		path  string
+		count int
+	}{
+		{"", 0},
+		{"a", 1},
+		{"abc", 1},
+		{"/", 2},
+		{"a/", 2},
+		{"a/b", 2},
+		{"a/b/c", 3},
+		{"//", 3},
+		{"a//b/", 4},
+	} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+
+		count := 0
+		for iter := NewIterator(tt.path); !iter.Done(); iter.Next() {
+			count++
+		}
+		assert.Equal(t, tt.count, count, errTag)
+
+		assert.Equal(t, tt.count, NewUnencrypted(tt.path).NumComponents(), errTag)
+		assert.Equal(t, tt.count, NewEncrypted(tt.path).NumComponents(), errTag)
+
+		allocs := testing.AllocsPerRun(10, func() {
+			_ = NewUnencrypted(tt.path).NumComponents()
+		})
+		assert.Zero(t, allocs, errTag)
+	}
+}
+
 func TestIterator(t *testing.T) {
 	for i, tt := range []struct {
 		path  string
@@ -0,0 +1,47 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pb
+
+import (
+	proto "github.com/gogo/protobuf/proto"
+	"github.com/zeebo/errs"
+)
+
+var batchError = errs.Class("Protobuf batch")
+
+// EstimateSize returns the number of bytes msg takes when sent as a
+// length-delimited element of a batch, including the framing overhead
+// of the field tag and length prefix.
+func EstimateSize(msg proto.Message) int {
+	size := proto.Size(msg)
+	// one byte for the field tag, followed by the varint encoded length.
+	return 1 + proto.SizeVarint(uint64(size)) + size
+}
+
+// SplitBatch groups msgs, preserving their order, so that the estimated size
+// of each group does not exceed maxBytes. It returns an error when a single
+// message is larger than maxBytes.
+func SplitBatch(msgs []proto.Message, maxBytes int) ([][]proto.Message, error) {
+	var batches [][]proto.Message
+	var current []proto.Message
+	currentSize := 0
+
+	for i, msg := range msgs {
+		size := EstimateSize(msg)
+		if size > maxBytes {
+			return nil, batchError.New("message %d of size %d exceeds limit %d", i, size, maxBytes)
+		}
+		if currentSize+size > maxBytes {
+			batches = append(batches, current)
+			current, currentSize = nil, 0
+		}
+		current = append(current, msg)
+		currentSize += size
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+
+	return batches, nil
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pb_test
+
+import (
+	"strings"
+	"testing"
+
+	proto "github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/pb"
+)
+
+func TestEstimateSize(t *testing.T) {
+	msg := &pb.NodeAddress{Address: strings.Repeat("a", 200)}
+	data, err := pb.Marshal(msg)
+	require.NoError(t, err)
+
+	// tag byte + two byte varint length + message
+	assert.Equal(t, 1+2+len(data), pb.EstimateSize(msg))
+}
+
+func TestSplitBatch(t *testing.T) {
+	var msgs []proto.Message
+	for i := 0; i < 10; i++ {
+		msgs = append(msgs, &pb.NodeAddress{Address: strings.Repeat("a", 10)})
+	}
+	size := pb.EstimateSize(msgs[0])
+
+	batches, err := pb.SplitBatch(msgs, 3*size+1)
+	require.NoError(t, err)
+	require.Len(t, batches, 4)
+
+	var total int
+	for _, batch := range batches {
+		batchSize := 0
+		for _, msg := range batch {
+			batchSize += pb.EstimateSize(msg)
+		}
+		assert.True(t, batchSize <= 3*size+1)
+		total += len(batch)
+	}
+	assert.Equal(t, len(msgs), total)
+	assert.Len(t, batches[3], 1)
+	assert.True(t, msgs[9] == batches[3][0])
+
+	batches, err = pb.SplitBatch(nil, size)
+	require.NoError(t, err)
+	assert.Empty(t, batches)
+}
+
+func TestSplitBatch_Oversized(t *testing.T) {
+	msgs := []proto.Message{
+		&pb.NodeAddress{Address: "small"},
+		&pb.NodeAddress{Address: strings.Repeat("a", 1000)},
+	}
+	_, err := pb.SplitBatch(msgs, 100)
+	require.Error(t, err)
+}
@@ -15,6 +15,9 @@ const (
 	// BlockLabelPrivateKey is the value to define a block label of general private key
 	// (used for PKCS#8-encoded private keys of type RSA, ECDSA, and others).
 	BlockLabelPrivateKey = "PRIVATE KEY"
+	// BlockLabelEncryptedPrivateKey is the value to define a block label of passphrase
+	// encrypted private key (used for encrypted PKCS#8-encoded private keys).
+	BlockLabelEncryptedPrivateKey = "ENCRYPTED PRIVATE KEY"
 	// BlockLabelPublicKey is the value to define a block label of general public key
 	// (used for PKIX-encoded public keys of type RSA, ECDSA, and others).
 	BlockLabelPublicKey = "PUBLIC KEY"
@@ -0,0 +1,124 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pkcrypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"math/big"
+)
+
+// HashAndSignDeterministic signs a SHA-256 digest of the given data like
+// HashAndSign, but the signature only depends on the key and the data: the
+// same data signed with the same key always yields the same signature.
+//
+// Only ECDSA keys are supported, see SignDeterministicWithoutHashing.
+func HashAndSignDeterministic(key crypto.PrivateKey, data []byte) ([]byte, error) {
+	return SignDeterministicWithoutHashing(key, SHA256Hash(data))
+}
+
+// SignDeterministicWithoutHashing signs the given digest with the private key
+// and returns the new signature. The ECDSA nonce is derived from the key and
+// the digest as specified by RFC 6979 with HMAC-SHA256 and the signature is
+// normalized to the low-S form, so the signature is deterministic. The
+// signatures verify with VerifySignatureWithoutHashing like any other.
+//
+// RSA-PSS signatures always use a random salt, so RSA keys are not supported.
+func SignDeterministicWithoutHashing(privKey crypto.PrivateKey, digest []byte) ([]byte, error) {
+	key, ok := privKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, ErrUnsupportedKey.New("%T", privKey)
+	}
+
+	r, s, err := signECDSADeterministic(key, digest)
+	if err != nil {
+		return nil, err
+	}
+	return marshalECDSASignature(r, s)
+}
+
+// signECDSADeterministic signs the digest with a nonce generated as specified
+// by RFC 6979 section 3.2 and returns the low-S signature.
+func signECDSADeterministic(privKey *ecdsa.PrivateKey, digest []byte) (r, s *big.Int, err error) {
+	curve := privKey.Curve
+	n := curve.Params().N
+	if n.Sign() == 0 || privKey.D == nil || privKey.D.Sign() <= 0 || privKey.D.Cmp(n) >= 0 {
+		return nil, nil, ErrSign.New("invalid ecdsa private key")
+	}
+
+	qlen := n.BitLen()
+	rlen := (qlen + 7) / 8
+
+	// bits2int interprets the leftmost qlen bits of data as an integer.
+	bits2int := func(data []byte) *big.Int {
+		v := new(big.Int).SetBytes(data)
+		if excess := len(data)*8 - qlen; excess > 0 {
+			v.Rsh(v, uint(excess))
+		}
+		return v
+	}
+	// int2octets encodes v as rlen big-endian bytes.
+	int2octets := func(v *big.Int) []byte {
+		out := make([]byte, rlen)
+		b := v.Bytes()
+		copy(out[rlen-len(b):], b)
+		return out
+	}
+
+	e := bits2int(digest)
+	x := int2octets(privKey.D)
+	h := int2octets(new(big.Int).Mod(e, n))
+
+	mac := func(key []byte, parts ...[]byte) []byte {
+		m := hmac.New(sha256.New, key)
+		for _, part := range parts {
+			_, _ = m.Write(part)
+		}
+		return m.Sum(nil)
+	}
+
+	v := make([]byte, sha256.Size)
+	for i := range v {
+		v[i] = 0x01
+	}
+	k := make([]byte, sha256.Size)
+
+	k = mac(k, v, []byte{0x00}, x, h)
+	v = mac(k, v)
+	k = mac(k, v, []byte{0x01}, x, h)
+	v = mac(k, v)
+
+	halfOrder := new(big.Int).Rsh(n, 1)
+	for {
+		var t []byte
+		for len(t) < rlen {
+			v = mac(k, v)
+			t = append(t, v...)
+		}
+
+		nonce := bits2int(t[:rlen])
+		if nonce.Sign() > 0 && nonce.Cmp(n) < 0 {
+			rx, _ := curve.ScalarBaseMult(int2octets(nonce))
+			r = new(big.Int).Mod(rx, n)
+			if r.Sign() != 0 {
+				// s = nonce^-1 * (e + r * d) mod n
+				s = new(big.Int).Mul(r, privKey.D)
+				s.Add(s, e)
+				s.Mul(s, new(big.Int).ModInverse(nonce, n))
+				s.Mod(s, n)
+				if s.Sign() != 0 {
+					if s.Cmp(halfOrder) > 0 {
+						s.Sub(n, s)
+					}
+					return r, s, nil
+				}
+			}
+		}
+
+		k = mac(k, v, []byte{0x00})
+		v = mac(k, v)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pkcrypto
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashAndSignDeterministic(t *testing.T) {
+	privKey, err := GeneratePrivateECDSAKey(authECCurve)
+	require.NoError(t, err)
+	pubKey, err := PublicKeyFromPrivate(privKey)
+	require.NoError(t, err)
+
+	halfOrder := new(big.Int).Rsh(authECCurve.Params().N, 1)
+	for _, data := range []string{"", "C", string(make([]byte, 2000))} {
+		first, err := HashAndSignDeterministic(privKey, []byte(data))
+		require.NoError(t, err)
+		second, err := HashAndSignDeterministic(privKey, []byte(data))
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+
+		require.NoError(t, HashAndVerifySignature(pubKey, []byte(data), first))
+
+		_, s, err := unmarshalECDSASignature(first)
+		require.NoError(t, err)
+		assert.True(t, s.Cmp(halfOrder) <= 0, "signature is not low-S")
+	}
+
+	other, err := HashAndSignDeterministic(privKey, []byte("other"))
+	require.NoError(t, err)
+	sig, err := HashAndSignDeterministic(privKey, []byte("data"))
+	require.NoError(t, err)
+	assert.NotEqual(t, sig, other)
+
+	rsaKey, err := GeneratePrivateRSAKey(StorjRSAKeyBits)
+	require.NoError(t, err)
+	_, err = HashAndSignDeterministic(rsaKey, []byte("data"))
+	require.True(t, ErrUnsupportedKey.Has(err), err)
+	require.False(t, ErrSign.Has(err), err)
+
+	// errors are wrapped only once
+	invalidKey := &ecdsa.PrivateKey{D: big.NewInt(0)}
+	invalidKey.Curve = authECCurve
+	_, err = HashAndSignDeterministic(invalidKey, []byte("data"))
+	require.True(t, ErrSign.Has(err), err)
+	require.Equal(t, 1, strings.Count(err.Error(), "unable to generate signature"), err)
+}
+
+func TestSignDeterministic_RFC6979(t *testing.T) {
+	hexInt := func(s string) *big.Int {
+		v, ok := new(big.Int).SetString(s, 16)
+		require.True(t, ok, s)
+		return v
+	}
+
+	// test vectors from RFC 6979 A.2.5 for P-256 with SHA-256
+	privKey := &ecdsa.PrivateKey{D: hexInt("C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721")}
+	privKey.Curve = authECCurve
+	privKey.X, privKey.Y = authECCurve.ScalarBaseMult(privKey.D.Bytes())
+	assert.Equal(t, hexInt("60FED4BA255A9D31C961EB74C6356D68C049B8923B61FA6CE669622E60F29FB6"), privKey.X)
+
+	n := authECCurve.Params().N
+	for _, test := range []struct {
+		message string
+		r, s    string
+	}{
+		{"sample",
+			"EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716",
+			"F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8"},
+		{"test",
+			"F1ABB023518351CD71D881567B1EA663ED3EFCF6C5132B354F28D3B0B7D38367",
+			"019F4113742A2B14BD25926B49C649155F267E60D3814B4C0CC84250E46F0083"},
+	} {
+		expectedS := hexInt(test.s)
+		if expectedS.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+			expectedS.Sub(n, expectedS)
+		}
+
+		r, s, err := signECDSADeterministic(privKey, SHA256Hash([]byte(test.message)))
+		require.NoError(t, err)
+		assert.Equal(t, hexInt(test.r), r, test.message)
+		assert.Equal(t, expectedS, s, test.message)
+	}
+}
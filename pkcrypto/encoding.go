@@ -4,6 +4,7 @@
 package pkcrypto
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/x509"
 	"encoding/asn1"
@@ -98,11 +99,70 @@ func PrivateKeyFromPEM(keyBytes []byte) (crypto.PrivateKey, error) {
 	if pb == nil {
 		return nil, ErrParse.New("could not parse PEM encoding")
 	}
+	if !isPrivateKeyBlock(pb) {
+		return nil, ErrParse.New("can not parse private key from PEM block labeled %q", pb.Type)
+	}
+	return privateKeyFromBlock(pb, nil)
+}
+
+// PrivateKeyFromEncryptedPEM parses a private key from its PEM-enveloped
+// PKCS#8 encoding, decrypting it with passphrase when the block is labeled
+// as an encrypted private key.
+func PrivateKeyFromEncryptedPEM(keyBytes, passphrase []byte) (crypto.PrivateKey, error) {
+	pb, _ := pem.Decode(keyBytes)
+	if pb == nil {
+		return nil, ErrParse.New("could not parse PEM encoding")
+	}
+	if !isPrivateKeyBlock(pb) {
+		return nil, ErrParse.New("can not parse private key from PEM block labeled %q", pb.Type)
+	}
+	return privateKeyFromBlock(pb, passphrase)
+}
+
+// ParsePrivateKeys parses all private keys from a byte string containing
+// concatenated PEM blocks. PEM blocks which don't contain private keys are
+// skipped.
+func ParsePrivateKeys(pemBytes []byte) ([]crypto.PrivateKey, error) {
+	var keys []crypto.PrivateKey
+	err := decodePEMBlocks(pemBytes, func(index int, pb *pem.Block) error {
+		if !isPrivateKeyBlock(pb) {
+			return nil
+		}
+		key, err := privateKeyFromBlock(pb, nil)
+		if err != nil {
+			return ErrParse.New("unable to parse private key in PEM block %d: %v", index, err)
+		}
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// isPrivateKeyBlock returns whether the PEM block is labeled as a private key.
+func isPrivateKeyBlock(pb *pem.Block) bool {
+	switch pb.Type {
+	case BlockLabelEcPrivateKey, BlockLabelPrivateKey, BlockLabelEncryptedPrivateKey:
+		return true
+	}
+	return false
+}
+
+// privateKeyFromBlock parses a private key from a PEM block labeled as a private key.
+// The passphrase is only used for encrypted private keys.
+func privateKeyFromBlock(pb *pem.Block, passphrase []byte) (crypto.PrivateKey, error) {
 	switch pb.Type {
 	case BlockLabelEcPrivateKey:
 		return ecPrivateKeyFromASN1(pb.Bytes)
 	case BlockLabelPrivateKey:
 		return PrivateKeyFromPKCS8(pb.Bytes)
+	case BlockLabelEncryptedPrivateKey:
+		if passphrase == nil {
+			return nil, ErrParse.New("private key is encrypted, passphrase required")
+		}
+		return PrivateKeyFromPKCS8Encrypted(pb.Bytes, passphrase)
 	}
 	return nil, ErrParse.New("can not parse private key from PEM block labeled %q", pb.Type)
 }
@@ -191,6 +251,47 @@ func CertsFromPEM(pemBytes []byte) ([]*x509.Certificate, error) {
 	return encChain.Parse()
 }
 
+// ParseCertificates parses all certificates from a byte string containing
+// concatenated PEM blocks. Unlike CertsFromPEM, malformed PEM data is reported
+// as an error instead of being silently ignored. PEM blocks which don't contain
+// certificates are skipped.
+func ParseCertificates(pemBytes []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	err := decodePEMBlocks(pemBytes, func(index int, pb *pem.Block) error {
+		if pb.Type != BlockLabelCertificate {
+			return nil
+		}
+		cert, err := CertFromDER(pb.Bytes)
+		if err != nil {
+			return ErrParse.New("unable to parse certificate in PEM block %d: %v", index, err)
+		}
+		certs = append(certs, cert)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
+
+// decodePEMBlocks calls fn for every PEM block in pemBytes. Text outside of
+// PEM blocks is ignored, but a block which can not be decoded is an error.
+func decodePEMBlocks(pemBytes []byte, fn func(index int, pb *pem.Block) error) error {
+	for index := 0; ; index++ {
+		var pemBlock *pem.Block
+		pemBlock, pemBytes = pem.Decode(pemBytes)
+		if pemBlock == nil {
+			if bytes.Contains(pemBytes, []byte("-----BEGIN")) {
+				return ErrParse.New("malformed PEM block %d", index)
+			}
+			return nil
+		}
+		if err := fn(index, pemBlock); err != nil {
+			return err
+		}
+	}
+}
+
 type encodedChain struct {
 	chain [][]byte
 }
@@ -0,0 +1,188 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pkcrypto
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"hash"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+var (
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA224 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 8}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+
+	oidAES128CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+// encryptedPrivateKeyInfo is the ASN.1 structure of an encrypted PKCS#8 key, see RFC 5208.
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// pbes2Params are the parameters of the PBES2 encryption scheme, see RFC 8018.
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params are the parameters of the PBKDF2 key derivation function, see RFC 8018.
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// PrivateKeyFromPKCS8Encrypted parses a private key from its passphrase
+// encrypted PKCS#8 encoding. Only the PBES2 scheme with PBKDF2 key derivation
+// and AES-CBC or DES-EDE3-CBC encryption is supported.
+func PrivateKeyFromPKCS8Encrypted(der []byte, passphrase []byte) (crypto.PrivateKey, error) {
+	var info encryptedPrivateKeyInfo
+	if rest, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, ErrParse.Wrap(err)
+	} else if len(rest) > 0 {
+		return nil, ErrParse.New("trailing data after encrypted private key")
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, ErrParse.New("unsupported encryption algorithm %v", info.Algorithm.Algorithm)
+	}
+
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, ErrParse.Wrap(err)
+	}
+
+	block, iv, err := pbes2Cipher(params.EncryptionScheme)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := pbes2DeriveKey(params.KeyDerivationFunc, passphrase, block.keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := block.decrypt(key, iv, info.EncryptedData)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := PrivateKeyFromPKCS8(plain)
+	if err != nil {
+		// a wrong passphrase may still produce valid padding
+		return nil, ErrParse.New("decryption failed: wrong passphrase or corrupted key")
+	}
+	return privateKey, nil
+}
+
+// pbes2BlockCipher describes a cbc block cipher used for PBES2 encryption.
+type pbes2BlockCipher struct {
+	keySize int
+	new     func(key []byte) (cipher.Block, error)
+}
+
+// pbes2Cipher returns the block cipher and iv for the encryption scheme.
+func pbes2Cipher(scheme pkix.AlgorithmIdentifier) (pbes2BlockCipher, []byte, error) {
+	var block pbes2BlockCipher
+	switch {
+	case scheme.Algorithm.Equal(oidAES128CBC):
+		block = pbes2BlockCipher{keySize: 16, new: aes.NewCipher}
+	case scheme.Algorithm.Equal(oidAES192CBC):
+		block = pbes2BlockCipher{keySize: 24, new: aes.NewCipher}
+	case scheme.Algorithm.Equal(oidAES256CBC):
+		block = pbes2BlockCipher{keySize: 32, new: aes.NewCipher}
+	case scheme.Algorithm.Equal(oidDESEDE3CBC):
+		block = pbes2BlockCipher{keySize: 24, new: des.NewTripleDESCipher}
+	default:
+		return pbes2BlockCipher{}, nil, ErrParse.New("unsupported encryption scheme %v", scheme.Algorithm)
+	}
+
+	var iv []byte
+	if _, err := asn1.Unmarshal(scheme.Parameters.FullBytes, &iv); err != nil {
+		return pbes2BlockCipher{}, nil, ErrParse.Wrap(err)
+	}
+	return block, iv, nil
+}
+
+// decrypt decrypts cbc encrypted data and removes the PKCS#7 padding.
+func (block pbes2BlockCipher) decrypt(key, iv, data []byte) ([]byte, error) {
+	c, err := block.new(key)
+	if err != nil {
+		return nil, ErrParse.Wrap(err)
+	}
+	blockSize := c.BlockSize()
+	if len(iv) != blockSize {
+		return nil, ErrParse.New("invalid iv length %d", len(iv))
+	}
+	if len(data) == 0 || len(data)%blockSize != 0 {
+		return nil, ErrParse.New("invalid encrypted data length %d", len(data))
+	}
+
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(c, iv).CryptBlocks(plain, data)
+
+	padding := int(plain[len(plain)-1])
+	if padding == 0 || padding > blockSize ||
+		!bytes.Equal(plain[len(plain)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, ErrParse.New("decryption failed: wrong passphrase or corrupted key")
+	}
+	return plain[:len(plain)-padding], nil
+}
+
+// pbes2DeriveKey derives the encryption key from the passphrase.
+func pbes2DeriveKey(kdf pkix.AlgorithmIdentifier, passphrase []byte, keySize int) ([]byte, error) {
+	if !kdf.Algorithm.Equal(oidPBKDF2) {
+		return nil, ErrParse.New("unsupported key derivation function %v", kdf.Algorithm)
+	}
+
+	var params pbkdf2Params
+	if _, err := asn1.Unmarshal(kdf.Parameters.FullBytes, &params); err != nil {
+		return nil, ErrParse.Wrap(err)
+	}
+	if params.IterationCount <= 0 {
+		return nil, ErrParse.New("invalid iteration count %d", params.IterationCount)
+	}
+	if params.KeyLength != 0 && params.KeyLength != keySize {
+		return nil, ErrParse.New("invalid key length %d", params.KeyLength)
+	}
+
+	var prf func() hash.Hash
+	switch {
+	case len(params.PRF.Algorithm) == 0, params.PRF.Algorithm.Equal(oidHMACWithSHA1):
+		prf = sha1.New
+	case params.PRF.Algorithm.Equal(oidHMACWithSHA224):
+		prf = sha256.New224
+	case params.PRF.Algorithm.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	case params.PRF.Algorithm.Equal(oidHMACWithSHA384):
+		prf = sha512.New384
+	case params.PRF.Algorithm.Equal(oidHMACWithSHA512):
+		prf = sha512.New
+	default:
+		return nil, ErrParse.New("unsupported pseudorandom function %v", params.PRF.Algorithm)
+	}
+
+	return pbkdf2.Key(passphrase, params.Salt, params.IterationCount, keySize, prf), nil
+}
@@ -9,6 +9,7 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"io"
 	"math/big"
 	"reflect"
 )
@@ -57,6 +58,17 @@ func HashAndVerifySignature(key crypto.PublicKey, data, signature []byte) error
 	return VerifySignatureWithoutHashing(key, digest, signature)
 }
 
+// VerifyStream checks that signature was made by the private key corresponding
+// to the given public key, over a SHA-256 digest of everything read from r.
+// The data is hashed incrementally, so it doesn't need to fit in memory.
+func VerifyStream(key crypto.PublicKey, r io.Reader, signature []byte) error {
+	h := NewHash()
+	if _, err := io.Copy(h, r); err != nil {
+		return ErrVerifySignature.New("unable to read data: %v", err)
+	}
+	return VerifySignatureWithoutHashing(key, h.Sum(nil), signature)
+}
+
 // VerifySignatureWithoutHashing checks the signature against the passed data
 // (which is normally a digest) and public key. It returns an error if
 // verification fails, or nil otherwise.
@@ -4,6 +4,9 @@
 package pkcrypto
 
 import (
+	"bytes"
+	"crypto/rand"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -97,3 +100,34 @@ func TestPublicKeyFromPrivate(t *testing.T) {
 	})
 
 }
+
+func TestVerifyStream(t *testing.T) {
+	privKey, err := GeneratePrivateKey()
+	require.NoError(t, err)
+	pubKey, err := PublicKeyFromPrivate(privKey)
+	require.NoError(t, err)
+
+	data := make([]byte, 10<<20)
+	_, err = rand.Read(data)
+	require.NoError(t, err)
+
+	sig, err := HashAndSign(privKey, data)
+	require.NoError(t, err)
+
+	err = VerifyStream(pubKey, bytes.NewReader(data), sig)
+	require.NoError(t, err)
+
+	data[len(data)/2]++
+	err = VerifyStream(pubKey, bytes.NewReader(data), sig)
+	require.Error(t, err)
+	require.True(t, ErrVerifySignature.Has(err))
+
+	err = VerifyStream(pubKey, failingReader{}, sig)
+	require.Error(t, err)
+	require.True(t, ErrVerifySignature.Has(err))
+}
+
+// failingReader fails every read.
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("read failed") }
@@ -0,0 +1,97 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ranger
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// Cancelable returns a Ranger whose readers respect the context passed to
+// Range. When the context is canceled, an in-flight Read returns ctx.Err()
+// without waiting for the underlying reader, and so do all the following
+// reads. Closing the reader closes the underlying reader, which should
+// unblock the abandoned Read.
+func Cancelable(r Ranger) Ranger {
+	return &cancelable{r: r}
+}
+
+type cancelable struct {
+	r Ranger
+}
+
+// Size implements Ranger.Size.
+func (c *cancelable) Size() int64 { return c.r.Size() }
+
+// Range implements Ranger.Range.
+func (c *cancelable) Range(ctx context.Context, offset, length int64) (_ io.ReadCloser, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	reader, err := c.r.Range(ctx, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	return &cancelableReader{
+		ctx:     ctx,
+		r:       reader,
+		results: make(chan readResult, 1),
+	}, nil
+}
+
+// readResult is the result of a Read done in the background.
+type readResult struct {
+	n   int
+	err error
+}
+
+// cancelableReader reads from r in a separate goroutine so that it can stop
+// waiting for the read when ctx is canceled. Once ctx is canceled no new
+// reads are started, so there is at most one read in the background.
+type cancelableReader struct {
+	ctx     context.Context
+	r       io.ReadCloser
+	buf     []byte
+	results chan readResult
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Read implements io.Reader.
+func (c *cancelableReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	// the read is done into a buffer owned by the reader, so that an abandoned
+	// read doesn't write into p after Read returned.
+	if cap(c.buf) < len(p) {
+		c.buf = make([]byte, len(p))
+	}
+	buf := c.buf[:len(p)]
+	go func() {
+		n, err := c.r.Read(buf)
+		c.results <- readResult{n, err}
+	}()
+
+	select {
+	case result := <-c.results:
+		n := copy(p, buf[:result.n])
+		return n, result.err
+	case <-c.ctx.Done():
+		return 0, c.ctx.Err()
+	}
+}
+
+// Close implements io.Closer.
+func (c *cancelableReader) Close() error {
+	c.closeOnce.Do(func() {
+		c.closeErr = c.r.Close()
+	})
+	return c.closeErr
+}
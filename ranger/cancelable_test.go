@@ -0,0 +1,85 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ranger
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testrand"
+)
+
+// stallingRanger returns readers that block on every Read until closed.
+type stallingRanger struct {
+	size   int64
+	closed chan struct{}
+}
+
+func (s *stallingRanger) Size() int64 { return s.size }
+
+func (s *stallingRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	return &stallingReader{closed: s.closed}, nil
+}
+
+type stallingReader struct {
+	closed chan struct{}
+}
+
+func (s *stallingReader) Read(p []byte) (int, error) {
+	<-s.closed
+	return 0, io.ErrClosedPipe
+}
+
+func (s *stallingReader) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func TestCancelable(t *testing.T) {
+	data := testrand.BytesInt(1024)
+	rr := Cancelable(ByteRanger(data))
+	require.Equal(t, int64(len(data)), rr.Size())
+
+	reader, err := rr.Range(context.Background(), 10, 100)
+	require.NoError(t, err)
+	read, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, data[10:110], read)
+
+	_, err = rr.Range(context.Background(), 1000, 100)
+	require.Error(t, err)
+}
+
+func TestCancelable_CancelMidRead(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source := &stallingRanger{size: 100, closed: make(chan struct{})}
+	reader, err := Cancelable(source).Range(ctx, 0, 100)
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	n, err := reader.Read(make([]byte, 10))
+	assert.Equal(t, 0, n)
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, time.Since(start) < 5*time.Second)
+
+	_, err = reader.Read(make([]byte, 10))
+	assert.Equal(t, context.Canceled, err)
+
+	require.NoError(t, reader.Close())
+	require.NoError(t, reader.Close())
+}
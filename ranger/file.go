@@ -75,6 +75,17 @@ func (reader *FileReader) Read(data []byte) (n int, err error) {
 	return
 }
 
+// WriteTo writes the remaining data to w. It lets io.Copy hand the file to
+// w directly, so writers such as network connections can use sendfile.
+func (reader *FileReader) WriteTo(w io.Writer) (n int64, err error) {
+	if reader.remaining <= 0 {
+		return 0, nil
+	}
+	n, err = io.Copy(w, io.LimitReader(reader.file, reader.remaining))
+	reader.remaining -= n
+	return n, err
+}
+
 // Close closes the underlying file.
 func (reader *FileReader) Close() error {
 	return reader.file.Close()
@@ -0,0 +1,45 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ranger
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// Lazy returns a Ranger of the given size that calls open to create the
+// backing Ranger on the first Range call. open is called only once and its
+// result, including an error, is used by all the following Range calls.
+func Lazy(size int64, open func() (Ranger, error)) Ranger {
+	return &lazy{size: size, open: open}
+}
+
+type lazy struct {
+	size int64
+	open func() (Ranger, error)
+
+	once sync.Once
+	r    Ranger
+	err  error
+}
+
+// Size implements Ranger.Size.
+func (l *lazy) Size() int64 { return l.size }
+
+// Range implements Ranger.Range.
+func (l *lazy) Range(ctx context.Context, offset, length int64) (_ io.ReadCloser, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	l.once.Do(func() {
+		l.r, l.err = l.open()
+		if l.err == nil && l.r.Size() != l.size {
+			l.err = Error.New("lazy ranger opened with size %d, expected %d", l.r.Size(), l.size)
+		}
+	})
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.r.Range(ctx, offset, length)
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ranger
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testrand"
+)
+
+func TestLazy(t *testing.T) {
+	ctx := context.Background()
+
+	data := testrand.BytesInt(256)
+	var opened int32
+	rr := Lazy(int64(len(data)), func() (Ranger, error) {
+		atomic.AddInt32(&opened, 1)
+		return ByteRanger(data), nil
+	})
+
+	require.Equal(t, int64(len(data)), rr.Size())
+	require.Equal(t, int32(0), atomic.LoadInt32(&opened))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			reader, err := rr.Range(ctx, int64(i), 16)
+			if !assert.NoError(t, err) {
+				return
+			}
+			read, err := ioutil.ReadAll(reader)
+			assert.NoError(t, err)
+			assert.NoError(t, reader.Close())
+			assert.Equal(t, data[i:i+16], read)
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&opened))
+}
+
+func TestLazy_Error(t *testing.T) {
+	ctx := context.Background()
+
+	failure := errors.New("open failed")
+	var opened int32
+	rr := Lazy(10, func() (Ranger, error) {
+		atomic.AddInt32(&opened, 1)
+		return nil, failure
+	})
+
+	for i := 0; i < 3; i++ {
+		_, err := rr.Range(ctx, 0, 10)
+		require.Equal(t, failure, err)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&opened))
+
+	mismatch := Lazy(10, func() (Ranger, error) {
+		return ByteRanger(make([]byte, 5)), nil
+	})
+	_, err := mismatch.Range(ctx, 0, 5)
+	require.Error(t, err)
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ranger
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"storj.io/common/sync2"
+)
+
+// RateLimited returns a Ranger whose readers together read no faster than
+// bytesPerSec. The budget is shared by all the readers returned by Range, so
+// opening a new range does not reset it. Reads wait for the budget while the
+// context passed to Range is active. If bytesPerSec is not positive, r is
+// returned unchanged.
+func RateLimited(r Ranger, bytesPerSec int64) Ranger {
+	if bytesPerSec <= 0 {
+		return r
+	}
+	return &rateLimited{
+		r:      r,
+		bucket: &tokenBucket{rate: bytesPerSec},
+	}
+}
+
+type rateLimited struct {
+	r      Ranger
+	bucket *tokenBucket
+}
+
+// Size implements Ranger.Size.
+func (rl *rateLimited) Size() int64 { return rl.r.Size() }
+
+// Range implements Ranger.Range.
+func (rl *rateLimited) Range(ctx context.Context, offset, length int64) (_ io.ReadCloser, err error) {
+	defer mon.Task()(&ctx)(&err)
+	rc, err := rl.r.Range(ctx, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	return &rateLimitedReader{ctx: ctx, rc: rc, bucket: rl.bucket}, nil
+}
+
+// rateLimitedReader waits for the bucket after every read.
+type rateLimitedReader struct {
+	ctx    context.Context
+	rc     io.ReadCloser
+	bucket *tokenBucket
+}
+
+// Read reads at most a tenth of a second worth of data from the underlying
+// reader and then waits until the rate allows it.
+func (r *rateLimitedReader) Read(p []byte) (n int, err error) {
+	if max := r.bucket.rate/10 + 1; int64(len(p)) > max {
+		p = p[:max]
+	}
+	n, err = r.rc.Read(p)
+	if waitErr := r.bucket.wait(r.ctx, int64(n)); waitErr != nil && err == nil {
+		err = waitErr
+	}
+	return n, err
+}
+
+// Close closes the underlying reader.
+func (r *rateLimitedReader) Close() error { return r.rc.Close() }
+
+// tokenBucket tracks how far ahead of its rate a group of readers is.
+type tokenBucket struct {
+	rate int64
+
+	mu sync.Mutex
+	// next is when all the bytes consumed so far are allowed by the rate.
+	next time.Time
+}
+
+// wait consumes n bytes and sleeps until the rate allows them.
+func (bucket *tokenBucket) wait(ctx context.Context, n int64) error {
+	if n <= 0 {
+		return nil
+	}
+
+	bucket.mu.Lock()
+	now := time.Now()
+	if bucket.next.Before(now) {
+		bucket.next = now
+	}
+	bucket.next = bucket.next.Add(time.Duration(n) * time.Second / time.Duration(bucket.rate))
+	delay := bucket.next.Sub(now)
+	bucket.mu.Unlock()
+
+	if !sync2.Sleep(ctx, delay) {
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ranger
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testrand"
+)
+
+func TestRateLimited(t *testing.T) {
+	ctx := context.Background()
+
+	const rate = 200 << 10
+	data := testrand.BytesInt(100 << 10)
+	rr := RateLimited(ByteRanger(data), rate)
+	require.Equal(t, int64(len(data)), rr.Size())
+
+	// the budget is shared between ranges, so reading two halves takes as
+	// long as reading the whole.
+	start := time.Now()
+	var read []byte
+	for _, offset := range []int64{0, int64(len(data)) / 2} {
+		rc, err := rr.Range(ctx, offset, int64(len(data))/2)
+		require.NoError(t, err)
+		part, err := ioutil.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		read = append(read, part...)
+	}
+	elapsed := time.Since(start)
+
+	assert.True(t, bytes.Equal(data, read))
+	expected := time.Duration(len(data)) * time.Second / rate
+	assert.True(t, elapsed >= expected*9/10, elapsed)
+	assert.True(t, elapsed <= expected*2, elapsed)
+}
+
+func TestRateLimited_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	rr := RateLimited(ByteRanger(testrand.BytesInt(10<<10)), 1<<10)
+	rc, err := rr.Range(ctx, 0, rr.Size())
+	require.NoError(t, err)
+
+	cancel()
+	start := time.Now()
+	_, err = ioutil.ReadAll(rc)
+	require.Equal(t, context.Canceled, err)
+	assert.True(t, time.Since(start) < time.Second)
+	require.NoError(t, rc.Close())
+}
+
+func TestRateLimited_Unlimited(t *testing.T) {
+	rr := ByteRanger([]byte("abc"))
+	assert.Equal(t, rr.Size(), RateLimited(rr, 0).Size())
+	_, ok := RateLimited(rr, 0).(ByteRanger)
+	assert.True(t, ok)
+}
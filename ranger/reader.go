@@ -39,7 +39,37 @@ func (b ByteRanger) Range(ctx context.Context, offset, length int64) (_ io.ReadC
 		return nil, Error.New("buffer runoff")
 	}
 
-	return ioutil.NopCloser(bytes.NewReader(b[offset : offset+length])), nil
+	return byteReadCloser{bytes.NewReader(b[offset : offset+length])}, nil
+}
+
+// byteReadCloser is a bytes.Reader with a no-op Close. It keeps the WriteTo
+// method of bytes.Reader so that io.Copy writes the data without a buffer.
+type byteReadCloser struct {
+	*bytes.Reader
+}
+
+// Close implements io.Closer.
+func (byteReadCloser) Close() error { return nil }
+
+// errorRanger is a Ranger that fails every Range call.
+type errorRanger struct {
+	size int64
+	err  error
+}
+
+// ErrorRanger returns a Ranger with the given size whose Range always returns err.
+// It allows deferring the handling of an error that occurred while constructing a Ranger.
+func ErrorRanger(size int64, err error) Ranger {
+	return &errorRanger{size: size, err: err}
+}
+
+// Size implements Ranger.Size.
+func (e *errorRanger) Size() int64 { return e.size }
+
+// Range implements Ranger.Range.
+func (e *errorRanger) Range(ctx context.Context, offset, length int64) (_ io.ReadCloser, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return nil, e.err
 }
 
 type concatReader struct {
@@ -115,3 +145,71 @@ func (s *subrange) Range(ctx context.Context, offset, length int64) (_ io.ReadCl
 	defer mon.Task()(&ctx)(&err)
 	return s.r.Range(ctx, offset+s.offset, length)
 }
+
+// FixedSize returns a Ranger of exactly size bytes. When r is shorter than
+// size, the remainder reads as zero bytes; when r is longer, it's truncated.
+func FixedSize(r Ranger, size int64) Ranger {
+	if size < 0 {
+		size = 0
+	}
+	if realSize := r.Size(); realSize < size {
+		r = concat2(r, zeroRanger(size-realSize))
+	}
+	return &fixedSize{r: r, size: size}
+}
+
+// fixedSize limits reads to the first size bytes of r.
+type fixedSize struct {
+	r    Ranger
+	size int64
+}
+
+// Size implements Ranger.Size.
+func (f *fixedSize) Size() int64 { return f.size }
+
+// Range implements Ranger.Range.
+func (f *fixedSize) Range(ctx context.Context, offset, length int64) (_ io.ReadCloser, err error) {
+	defer mon.Task()(&ctx)(&err)
+	if offset < 0 {
+		return nil, Error.New("negative offset")
+	}
+	if length < 0 {
+		return nil, Error.New("negative length")
+	}
+	if offset+length > f.size {
+		return nil, Error.New("range beyond fixed size")
+	}
+	return f.r.Range(ctx, offset, length)
+}
+
+// zeroRanger is a Ranger of the given size that reads as zero bytes.
+type zeroRanger int64
+
+// Size implements Ranger.Size.
+func (z zeroRanger) Size() int64 { return int64(z) }
+
+// Range implements Ranger.Range.
+func (z zeroRanger) Range(ctx context.Context, offset, length int64) (_ io.ReadCloser, err error) {
+	defer mon.Task()(&ctx)(&err)
+	if offset < 0 {
+		return nil, Error.New("negative offset")
+	}
+	if length < 0 {
+		return nil, Error.New("negative length")
+	}
+	if offset+length > int64(z) {
+		return nil, Error.New("buffer runoff")
+	}
+
+	return ioutil.NopCloser(io.LimitReader(zeroReader{}, length)), nil
+}
+
+// zeroReader is an endless reader of zero bytes.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
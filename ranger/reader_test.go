@@ -7,12 +7,29 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
 )
 
+func TestErrorRanger(t *testing.T) {
+	failure := Error.New("lazy open failed")
+	rr := ErrorRanger(10, failure)
+	assert.Equal(t, int64(10), rr.Size())
+
+	for _, length := range []int64{0, 5, 10} {
+		rc, err := rr.Range(context.Background(), 0, length)
+		assert.Nil(t, rc)
+		assert.Equal(t, failure, err)
+	}
+}
+
 func TestByteRanger(t *testing.T) {
 	for _, example := range []struct {
 		data                 string
@@ -154,3 +171,131 @@ func TestSubrangerError(t *testing.T) {
 		assert.NotNil(t, err, tag)
 	}
 }
+
+func TestFixedSize(t *testing.T) {
+	for _, example := range []struct {
+		data           string
+		size           int64
+		offset, length int64
+		expected       string
+	}{
+		{"abcd", 4, 0, 4, "abcd"},
+		{"abcd", 6, 0, 6, "abcd\x00\x00"},
+		{"abcd", 6, 2, 3, "cd\x00"},
+		{"abcd", 6, 4, 2, "\x00\x00"},
+		{"abcd", 6, 5, 0, ""},
+		{"abcdef", 4, 0, 4, "abcd"},
+		{"abcdef", 4, 2, 2, "cd"},
+		{"", 3, 0, 3, "\x00\x00\x00"},
+		{"abcd", 0, 0, 0, ""},
+	} {
+		tag := fmt.Sprintf("%+v", example)
+
+		rr := FixedSize(ByteRanger([]byte(example.data)), example.size)
+		assert.Equal(t, example.size, rr.Size(), tag)
+
+		rc, err := rr.Range(context.Background(), example.offset, example.length)
+		if !assert.NoError(t, err, tag) {
+			continue
+		}
+		data, err := ioutil.ReadAll(rc)
+		assert.NoError(t, err, tag)
+		assert.NoError(t, rc.Close(), tag)
+		assert.Equal(t, example.expected, string(data), tag)
+	}
+}
+
+func TestFixedSizeError(t *testing.T) {
+	ctx := context.Background()
+
+	padded := FixedSize(ByteRanger([]byte("abcd")), 6)
+	_, err := padded.Range(ctx, 4, 3)
+	assert.Error(t, err)
+
+	truncated := FixedSize(ByteRanger([]byte("abcdef")), 4)
+	_, err = truncated.Range(ctx, 2, 3)
+	assert.Error(t, err)
+}
+
+// newTestRangers returns a ByteRanger, a FileRanger and a ReaderAtRanger with the same data.
+func newTestRangers(t testing.TB, ctx *testcontext.Context, data []byte) map[string]Ranger {
+	path := ctx.File("data")
+	require.NoError(t, ioutil.WriteFile(path, data, 0644))
+	fileRanger, err := FileRanger(path)
+	require.NoError(t, err)
+
+	return map[string]Ranger{
+		"bytes":    ByteRanger(data),
+		"file":     fileRanger,
+		"readerat": ReaderAtRanger(bytes.NewReader(data), int64(len(data))),
+	}
+}
+
+func TestWriterTo(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	data := testrand.BytesInt(1 << 20)
+
+	for name, rr := range newTestRangers(t, ctx, data) {
+		for _, span := range []struct{ offset, length int64 }{
+			{0, rr.Size()},
+			{0, 0},
+			{1, rr.Size() - 1},
+			{12345, 1 << 19},
+			{rr.Size() - 1, 1},
+		} {
+			tag := fmt.Sprintf("%s %+v", name, span)
+
+			rc, err := rr.Range(ctx, span.offset, span.length)
+			require.NoError(t, err, tag)
+			require.Implements(t, (*io.WriterTo)(nil), rc, tag)
+
+			var buf bytes.Buffer
+			n, err := io.Copy(&buf, rc)
+			require.NoError(t, err, tag)
+			assert.Equal(t, span.length, n, tag)
+			assert.True(t, bytes.Equal(data[span.offset:span.offset+span.length], buf.Bytes()), tag)
+
+			// everything has been consumed
+			n, err = rc.(io.WriterTo).WriteTo(&buf)
+			require.NoError(t, err, tag)
+			assert.Equal(t, int64(0), n, tag)
+			require.NoError(t, rc.Close(), tag)
+		}
+	}
+}
+
+func BenchmarkWriterTo(b *testing.B) {
+	ctx := testcontext.New(b)
+	defer ctx.Cleanup()
+
+	data := testrand.BytesInt(16 << 20)
+
+	for name, rr := range newTestRangers(b, ctx, data) {
+		rr := rr
+		for _, mode := range []string{"WriterTo", "generic"} {
+			mode := mode
+			b.Run(name+"/"+mode, func(b *testing.B) {
+				b.SetBytes(rr.Size())
+				for i := 0; i < b.N; i++ {
+					rc, err := rr.Range(ctx, 0, rr.Size())
+					if err != nil {
+						b.Fatal(err)
+					}
+					var src io.Reader = rc
+					if mode == "generic" {
+						// hide WriteTo so io.Copy uses its own buffer
+						src = struct{ io.Reader }{rc}
+					}
+					if _, err := io.Copy(ioutil.Discard, src); err != nil {
+						b.Fatal(err)
+					}
+					if err := rc.Close(); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
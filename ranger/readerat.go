@@ -6,6 +6,8 @@ package ranger
 import (
 	"context"
 	"io"
+
+	"github.com/zeebo/errs"
 )
 
 type readerAtRanger struct {
@@ -57,6 +59,70 @@ func (r *readerAtReader) Read(p []byte) (n int, err error) {
 	return n, err
 }
 
+// readerAtCopyBufferSize is the size of the buffer WriteTo reads into.
+const readerAtCopyBufferSize = 256 << 10
+
+// WriteTo writes the remaining data to w, reading it in larger chunks than
+// the default buffer of io.Copy.
+func (r *readerAtReader) WriteTo(w io.Writer) (n int64, err error) {
+	if r.length == 0 {
+		return 0, nil
+	}
+	size := int64(readerAtCopyBufferSize)
+	if r.length < size {
+		size = r.length
+	}
+	n, err = io.CopyBuffer(w, io.NewSectionReader(r.r, r.offset, r.length), make([]byte, size))
+	r.offset += n
+	r.length -= n
+	return n, err
+}
+
 func (r *readerAtReader) Close() error {
 	return nil
 }
+
+type rangerReaderAt struct {
+	r Ranger
+}
+
+// AsReaderAt converts a Ranger to an io.ReaderAt. Every ReadAt call opens a
+// new range, so it is safe to call ReadAt concurrently.
+func AsReaderAt(r Ranger) io.ReaderAt {
+	return &rangerReaderAt{r: r}
+}
+
+func (r *rangerReaderAt) ReadAt(p []byte, off int64) (n int, err error) {
+	ctx := context.Background()
+	defer mon.Task()(&ctx)(&err)
+
+	if off < 0 {
+		return 0, Error.New("negative offset")
+	}
+
+	size := r.r.Size()
+	if off >= size {
+		return 0, io.EOF
+	}
+
+	length := int64(len(p))
+	if off+length > size {
+		length = size - off
+	}
+
+	rc, err := r.r.Range(ctx, off, length)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { err = errs.Combine(err, rc.Close()) }()
+
+	n, err = io.ReadFull(rc, p[:length])
+	if err != nil {
+		return n, err
+	}
+	if length < int64(len(p)) {
+		// the ReaderAt contract requires a non-nil error for short reads.
+		return n, io.EOF
+	}
+	return n, nil
+}
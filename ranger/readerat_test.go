@@ -4,11 +4,18 @@
 package ranger
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/common/testrand"
 )
 
 func TestRange(t *testing.T) {
@@ -33,3 +40,51 @@ func TestClose(t *testing.T) {
 	rr := readerAtReader{length: 0}
 	assert.Nil(t, rr.Close())
 }
+
+func TestAsReaderAt(t *testing.T) {
+	data := testrand.BytesInt(10 * 1024)
+	readerAt := AsReaderAt(ByteRanger(data))
+
+	var group errgroup.Group
+	for i := 0; i < 10; i++ {
+		group.Go(func() error {
+			for k := 0; k < 100; k++ {
+				offset := testrand.Int63n(int64(len(data)))
+				length := testrand.Int63n(int64(len(data)) - offset + 1)
+
+				section := io.NewSectionReader(readerAt, offset, length)
+				read, err := ioutil.ReadAll(section)
+				if err != nil {
+					return err
+				}
+				if !bytes.Equal(data[offset:offset+length], read) {
+					return fmt.Errorf("mismatch at offset %d length %d", offset, length)
+				}
+			}
+			return nil
+		})
+	}
+	require.NoError(t, group.Wait())
+}
+
+func TestAsReaderAt_EOF(t *testing.T) {
+	readerAt := AsReaderAt(ByteRanger([]byte("abcdef")))
+
+	buf := make([]byte, 4)
+	n, err := readerAt.ReadAt(buf, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, "abcd", string(buf))
+
+	n, err = readerAt.ReadAt(buf, 4)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "ef", string(buf[:n]))
+
+	n, err = readerAt.ReadAt(buf, 6)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 0, n)
+
+	_, err = readerAt.ReadAt(buf, -1)
+	assert.Error(t, err)
+}
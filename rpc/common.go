@@ -27,6 +27,12 @@ var mon = monkit.Package()
 // Error wraps all of the errors returned by this package.
 var Error = errs.Class("rpc")
 
+// ErrDialTimeout is returned when a dial does not complete within the Dialer's DialTimeout.
+var ErrDialTimeout = errs.Class("dial timeout")
+
+// ErrPinMismatch is returned when a server does not present the Dialer's PinnedCertificate.
+var ErrPinMismatch = errs.Class("certificate pin mismatch")
+
 //
 // timed conns
 //
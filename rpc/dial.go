@@ -4,8 +4,12 @@
 package rpc
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"net"
 	"time"
 
@@ -38,7 +42,9 @@ type Dialer struct {
 	TLSOptions *tlsopts.Options
 
 	// DialTimeout causes all the tcp dials to error if they take longer
-	// than it if it is non-zero.
+	// than it if it is non-zero. It only bounds establishing the connection
+	// and the handshake, the returned connection is not affected by it.
+	// Dials that time out return an ErrDialTimeout error.
 	DialTimeout time.Duration
 
 	// DialLatency sleeps this amount if it is non-zero before every dial.
@@ -53,6 +59,12 @@ type Dialer struct {
 
 	// Connector is how sockets are opened. If nil, net.Dialer is used.
 	Connector Connector
+
+	// PinnedCertificate is the DER encoded leaf certificate that servers must
+	// present, in addition to the usual verification, if it is non-empty.
+	// Dials to servers presenting a different leaf return an ErrPinMismatch
+	// error.
+	PinnedCertificate []byte
 }
 
 // NewDefaultDialer returns a Dialer with default options set.
@@ -92,8 +104,8 @@ func (d Dialer) DialNodeURL(ctx context.Context, nodeURL storj.NodeURL) (_ *Conn
 		return nil, Error.New("tls options not set when required for this dial")
 	}
 
-	return d.dialPool(ctx, "node:"+nodeURL.ID.String(), func(ctx context.Context) (drpc.Conn, error) {
-		return d.dialEncryptedConn(ctx, nodeURL.Address, d.TLSOptions.ClientTLSConfig(nodeURL.ID))
+	return d.dialPool(ctx, "node:"+nodeURL.ID.String()+d.pinKey(), func(ctx context.Context) (drpc.Conn, error) {
+		return d.dialEncryptedConn(ctx, nodeURL.Address, d.pinTLSConfig(d.TLSOptions.ClientTLSConfig(nodeURL.ID)))
 	})
 }
 
@@ -105,8 +117,8 @@ func (d Dialer) DialAddressInsecure(ctx context.Context, address string) (_ *Con
 		return nil, Error.New("tls options not set when required for this dial")
 	}
 
-	return d.dialPool(ctx, "insecure:"+address, func(ctx context.Context) (drpc.Conn, error) {
-		return d.dialEncryptedConn(ctx, address, d.TLSOptions.UnverifiedClientTLSConfig())
+	return d.dialPool(ctx, "insecure:"+address+d.pinKey(), func(ctx context.Context) (drpc.Conn, error) {
+		return d.dialEncryptedConn(ctx, address, d.pinTLSConfig(d.TLSOptions.UnverifiedClientTLSConfig()))
 	})
 }
 
@@ -126,20 +138,57 @@ func (d Dialer) DialAddressUnencrypted(ctx context.Context, address string) (_ *
 // dialing helper functions
 //
 
+// pinKey returns the suffix for pool keys that distinguishes connections
+// verified against the pinned certificate.
+func (d Dialer) pinKey() string {
+	if len(d.PinnedCertificate) == 0 {
+		return ""
+	}
+	hash := sha256.Sum256(d.PinnedCertificate)
+	return ":pin:" + hex.EncodeToString(hash[:])
+}
+
+// pinTLSConfig adds the check for the pinned certificate to the tls config.
+func (d Dialer) pinTLSConfig(config *tls.Config) *tls.Config {
+	if len(d.PinnedCertificate) == 0 {
+		return config
+	}
+
+	pinned := d.PinnedCertificate
+	verify := config.VerifyPeerCertificate
+	config.VerifyPeerCertificate = func(rawCerts [][]byte, chains [][]*x509.Certificate) error {
+		if verify != nil {
+			if err := verify(rawCerts, chains); err != nil {
+				return err
+			}
+		}
+		if len(rawCerts) == 0 || !bytes.Equal(rawCerts[0], pinned) {
+			return ErrPinMismatch.New("peer leaf certificate does not match the pinned certificate")
+		}
+		return nil
+	}
+	return config
+}
+
 // dialPool dials through the connection pool, reusing a connection if possible based on the
 // key and calling the dialer if necessary.
 func (d Dialer) dialPool(ctx context.Context, key string, dialer rpcpool.Dialer) (_ *Conn, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	// include the timeout here so that it includes all aspects of the dial
+	dialCtx := ctx
 	if d.DialTimeout > 0 {
 		var cancel func()
-		ctx, cancel = context.WithTimeout(ctx, d.DialTimeout)
+		dialCtx, cancel = context.WithTimeout(ctx, d.DialTimeout)
 		defer cancel()
 	}
 
-	conn, err := d.Pool.Get(ctx, key, d.TLSOptions, dialer)
+	conn, err := d.Pool.Get(dialCtx, key, d.TLSOptions, dialer)
 	if err != nil {
+		// distinguish our timeout from the caller's context ending.
+		if ctx.Err() == nil && dialCtx.Err() == context.DeadlineExceeded {
+			return nil, ErrDialTimeout.Wrap(err)
+		}
 		return nil, errs.Wrap(err)
 	}
 
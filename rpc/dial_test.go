@@ -0,0 +1,111 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+)
+
+// serveStalled accepts connections on the listener and never responds to them.
+func serveStalled(ctx *testcontext.Context, listener net.Listener) {
+	ctx.Go(func() error {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				_ = conn.Close()
+			}
+		}()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return nil
+			}
+			conns = append(conns, conn)
+		}
+	})
+}
+
+func TestDialer_DialTimeout(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ctx.Check(listener.Close)
+	serveStalled(ctx, listener)
+
+	dialer := NewDefaultDialer(newTestTLSOptions(t, 0))
+
+	t.Run("dial timeout", func(t *testing.T) {
+		dialer.DialTimeout = 100 * time.Millisecond
+
+		dialCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		_, err := dialer.DialAddressInsecure(dialCtx, listener.Addr().String())
+		require.Error(t, err)
+		assert.True(t, ErrDialTimeout.Has(err))
+		assert.NoError(t, dialCtx.Err())
+	})
+
+	t.Run("context deadline", func(t *testing.T) {
+		dialer.DialTimeout = time.Minute
+
+		dialCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+
+		_, err := dialer.DialAddressInsecure(dialCtx, listener.Addr().String())
+		require.Error(t, err)
+		assert.False(t, ErrDialTimeout.Has(err))
+	})
+}
+
+func TestDialer_PinnedCertificate(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	serverOpts := newTestTLSOptions(t, 0)
+	clientOpts := newTestTLSOptions(t, 1)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ctx.Check(listener.Close)
+	serveTestTLS(ctx, listener, serverOpts)
+
+	nodeURL := storj.NodeURL{ID: serverOpts.Ident.ID, Address: listener.Addr().String()}
+	dialer := NewDefaultDialer(clientOpts)
+
+	t.Run("matching pin", func(t *testing.T) {
+		dialer.PinnedCertificate = serverOpts.Cert.Certificate[0]
+
+		conn, err := dialer.DialNodeURL(ctx, nodeURL)
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+
+		conn, err = dialer.DialAddressInsecure(ctx, nodeURL.Address)
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+	})
+
+	t.Run("mismatched pin", func(t *testing.T) {
+		dialer.PinnedCertificate = clientOpts.Cert.Certificate[0]
+
+		_, err := dialer.DialNodeURL(ctx, nodeURL)
+		require.Error(t, err)
+		assert.True(t, ErrPinMismatch.Has(err))
+
+		_, err = dialer.DialAddressInsecure(ctx, nodeURL.Address)
+		require.Error(t, err)
+		assert.True(t, ErrPinMismatch.Has(err))
+	})
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/storj"
+)
+
+// ErrUnreachable is returned by Ping when the node could not be reached.
+var ErrUnreachable = errs.Class("node unreachable")
+
+// Ping checks that the node is reachable by establishing a new connection to
+// it and completing the tls handshake, which verifies the node id. It returns
+// how long that took. The connection pool is not used and the connection is
+// closed before returning. The Dialer's DialTimeout bounds the attempt.
+func Ping(ctx context.Context, dialer Dialer, url storj.NodeURL) (_ time.Duration, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if dialer.TLSOptions == nil {
+		return 0, Error.New("tls options not set when required for this dial")
+	}
+	if dialer.Connector == nil {
+		return 0, Error.New("connector not set when required for this dial")
+	}
+
+	if dialer.DialTimeout > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, dialer.DialTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	conn, err := dialer.Connector.DialContext(ctx, dialer.TLSOptions.ClientTLSConfig(url.ID), url.Address)
+	if err != nil {
+		return 0, ErrUnreachable.Wrap(err)
+	}
+	latency := time.Since(start)
+
+	return latency, Error.Wrap(conn.Close())
+}
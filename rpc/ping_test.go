@@ -0,0 +1,65 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package rpc
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+)
+
+func TestPing(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	serverOpts := newTestTLSOptions(t, 0)
+	dialer := NewDefaultDialer(newTestTLSOptions(t, 1))
+	dialer.DialTimeout = 10 * time.Second
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ctx.Check(listener.Close)
+	serveTestTLS(ctx, listener, serverOpts)
+
+	latency, err := Ping(ctx, dialer, storj.NodeURL{
+		ID:      serverOpts.Ident.ID,
+		Address: listener.Addr().String(),
+	})
+	require.NoError(t, err)
+	assert.True(t, latency > 0)
+
+	// the node id is verified
+	_, err = Ping(ctx, dialer, storj.NodeURL{
+		ID:      newTestTLSOptions(t, 2).Ident.ID,
+		Address: listener.Addr().String(),
+	})
+	require.Error(t, err)
+	assert.True(t, ErrUnreachable.Has(err))
+}
+
+func TestPing_DeadAddress(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	dialer := NewDefaultDialer(newTestTLSOptions(t, 1))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	latency, err := Ping(ctx, dialer, storj.NodeURL{
+		ID:      newTestTLSOptions(t, 0).Ident.ID,
+		Address: address,
+	})
+	require.Error(t, err)
+	assert.True(t, ErrUnreachable.Has(err))
+	assert.Equal(t, time.Duration(0), latency)
+}
@@ -0,0 +1,289 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/zeebo/errs"
+)
+
+// ConnectorWithProxy returns a TCPConnector that establishes all tcp
+// connections through the proxy at proxyURL before performing the tls
+// handshake with the target. The tls handshake still verifies the target,
+// so the proxy is not trusted.
+//
+// Supported proxy url schemes are "socks5" and "http". Credentials in the url
+// are used to authenticate with the proxy.
+func ConnectorWithProxy(proxyURL *url.URL) (TCPConnector, error) {
+	adapter, err := NewProxyConnectorAdapter(proxyURL)
+	if err != nil {
+		return TCPConnector{}, err
+	}
+	return NewDefaultTCPConnector(adapter), nil
+}
+
+// NewProxyConnectorAdapter returns a ConnectorAdapter that dials tcp
+// connections through the proxy at proxyURL.
+func NewProxyConnectorAdapter(proxyURL *url.URL) (*ConnectorAdapter, error) {
+	if proxyURL == nil {
+		return nil, Error.New("proxy url not set")
+	}
+
+	proxy := &proxyDialer{
+		address: proxyURL.Host,
+		user:    proxyURL.User,
+		dialer:  new(net.Dialer),
+	}
+
+	switch proxyURL.Scheme {
+	case "socks5":
+		proxy.connect = proxy.connectSOCKS5
+		if proxyURL.Port() == "" {
+			proxy.address = net.JoinHostPort(proxyURL.Hostname(), "1080")
+		}
+	case "http":
+		proxy.connect = proxy.connectHTTP
+		if proxyURL.Port() == "" {
+			proxy.address = net.JoinHostPort(proxyURL.Hostname(), "80")
+		}
+	default:
+		return nil, Error.New("unsupported proxy scheme: %q", proxyURL.Scheme)
+	}
+
+	return &ConnectorAdapter{DialContext: proxy.DialContext}, nil
+}
+
+// proxyDialer dials connections through a proxy.
+type proxyDialer struct {
+	address string
+	user    *url.Userinfo
+	dialer  *net.Dialer
+	connect func(conn net.Conn, address string) (net.Conn, error)
+}
+
+// DialContext connects to the proxy and asks it to open a connection to address.
+func (p *proxyDialer) DialContext(ctx context.Context, network, address string) (_ net.Conn, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if network != "tcp" {
+		return nil, Error.New("unsupported network for proxy: %q", network)
+	}
+
+	conn, err := p.dialer.DialContext(ctx, network, p.address)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, errs.Combine(Error.Wrap(err), conn.Close())
+		}
+	}
+
+	proxied, err := p.connect(conn, address)
+	if err != nil {
+		return nil, errs.Combine(Error.Wrap(err), conn.Close())
+	}
+
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return nil, errs.Combine(Error.Wrap(err), conn.Close())
+	}
+
+	return proxied, nil
+}
+
+// socks5 protocol constants, see RFC 1928 and RFC 1929.
+const (
+	socks5Version          = 0x05
+	socks5AuthNone         = 0x00
+	socks5AuthPassword     = 0x02
+	socks5AuthNoAcceptable = 0xff
+	socks5CmdConnect       = 0x01
+	socks5AddrIPv4         = 0x01
+	socks5AddrDomain       = 0x03
+	socks5AddrIPv6         = 0x04
+	socks5ReplySucceeded   = 0x00
+	socks5PasswordVersion  = 0x01
+)
+
+// connectSOCKS5 performs the socks5 handshake asking the proxy to connect to address.
+func (p *proxyDialer) connectSOCKS5(conn net.Conn, address string) (net.Conn, error) {
+	host, portString, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.ParseUint(portString, 10, 16)
+	if err != nil {
+		return nil, errs.New("invalid port %q", portString)
+	}
+
+	methods := []byte{socks5AuthNone}
+	if p.user != nil {
+		methods = append(methods, socks5AuthPassword)
+	}
+
+	greeting := append([]byte{socks5Version, byte(len(methods))}, methods...)
+	if _, err := conn.Write(greeting); err != nil {
+		return nil, err
+	}
+
+	var choice [2]byte
+	if _, err := io.ReadFull(conn, choice[:]); err != nil {
+		return nil, err
+	}
+	if choice[0] != socks5Version {
+		return nil, errs.New("unexpected socks version %d", choice[0])
+	}
+
+	switch choice[1] {
+	case socks5AuthNone:
+	case socks5AuthPassword:
+		if p.user == nil {
+			return nil, errs.New("socks5 proxy requires authentication")
+		}
+		if err := p.authenticateSOCKS5(conn); err != nil {
+			return nil, err
+		}
+	case socks5AuthNoAcceptable:
+		return nil, errs.New("socks5 proxy did not accept any authentication method")
+	default:
+		return nil, errs.New("socks5 proxy selected unsupported authentication method %d", choice[1])
+	}
+
+	request := []byte{socks5Version, socks5CmdConnect, 0}
+	if ip := net.ParseIP(host); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			request = append(request, socks5AddrIPv4)
+			request = append(request, ip4...)
+		} else {
+			request = append(request, socks5AddrIPv6)
+			request = append(request, ip.To16()...)
+		}
+	} else {
+		if len(host) > 255 {
+			return nil, errs.New("host name too long: %q", host)
+		}
+		request = append(request, socks5AddrDomain, byte(len(host)))
+		request = append(request, host...)
+	}
+	request = append(request, byte(port>>8), byte(port))
+
+	if _, err := conn.Write(request); err != nil {
+		return nil, err
+	}
+
+	var reply [4]byte
+	if _, err := io.ReadFull(conn, reply[:]); err != nil {
+		return nil, err
+	}
+	if reply[0] != socks5Version {
+		return nil, errs.New("unexpected socks version %d", reply[0])
+	}
+	if reply[1] != socks5ReplySucceeded {
+		return nil, errs.New("socks5 proxy failed to connect to %q: reply code %d", address, reply[1])
+	}
+
+	// discard the bound address and port the server reports
+	var boundLength int
+	switch reply[3] {
+	case socks5AddrIPv4:
+		boundLength = net.IPv4len
+	case socks5AddrIPv6:
+		boundLength = net.IPv6len
+	case socks5AddrDomain:
+		var length [1]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return nil, err
+		}
+		boundLength = int(length[0])
+	default:
+		return nil, errs.New("socks5 proxy replied with unknown address type %d", reply[3])
+	}
+	bound := make([]byte, boundLength+2)
+	if _, err := io.ReadFull(conn, bound); err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// authenticateSOCKS5 performs the socks5 username/password authentication.
+func (p *proxyDialer) authenticateSOCKS5(conn net.Conn) error {
+	username := p.user.Username()
+	password, _ := p.user.Password()
+	if len(username) > 255 || len(password) > 255 {
+		return errs.New("socks5 credentials too long")
+	}
+
+	request := []byte{socks5PasswordVersion, byte(len(username))}
+	request = append(request, username...)
+	request = append(request, byte(len(password)))
+	request = append(request, password...)
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+
+	var reply [2]byte
+	if _, err := io.ReadFull(conn, reply[:]); err != nil {
+		return err
+	}
+	if reply[1] != 0 {
+		return errs.New("socks5 proxy authentication failed")
+	}
+	return nil
+}
+
+// connectHTTP sends a CONNECT request asking the proxy to connect to address.
+func (p *proxyDialer) connectHTTP(conn net.Conn, address string) (net.Conn, error) {
+	request := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if p.user != nil {
+		password, _ := p.user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(p.user.Username() + ":" + password))
+		request.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err := request.Write(conn); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		return nil, err
+	}
+	_ = response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, errs.New("http proxy failed to connect to %q: %s", address, response.Status)
+	}
+
+	// the proxy may have sent data past the response, so keep using what's buffered.
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn is a net.Conn that reads through a buffered reader first.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// Read reads from the buffered reader.
+func (c *bufferedConn) Read(p []byte) (int, error) { return c.reader.Read(p) }
@@ -0,0 +1,268 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/identity/testidentity"
+	"storj.io/common/peertls/tlsopts"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+)
+
+// newTestTLSOptions returns tls options using a pregenerated identity.
+func newTestTLSOptions(t *testing.T, index int) *tlsopts.Options {
+	ident, err := testidentity.PregeneratedIdentity(index, storj.LatestIDVersion())
+	require.NoError(t, err)
+
+	opts, err := tlsopts.NewOptions(ident, tlsopts.Config{PeerIDVersions: "*"}, nil)
+	require.NoError(t, err)
+	return opts
+}
+
+// serveTestTLS accepts connections on the listener, strips the drpc header and
+// completes a tls handshake before echoing back everything that was read.
+func serveTestTLS(ctx *testcontext.Context, listener net.Listener, opts *tlsopts.Options) {
+	ctx.Go(func() error {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return nil
+			}
+			ctx.Go(func() error {
+				defer func() { _ = conn.Close() }()
+
+				header := make([]byte, len(drpcHeader))
+				if _, err := io.ReadFull(conn, header); err != nil {
+					return nil
+				}
+
+				tlsConn := tls.Server(conn, opts.ServerTLSConfig())
+				_, _ = io.Copy(tlsConn, tlsConn)
+				return nil
+			})
+		}
+	})
+}
+
+// socks5Stub is a minimal socks5 proxy without authentication.
+type socks5Stub struct {
+	listener net.Listener
+
+	mu      sync.Mutex
+	targets []string
+}
+
+func newSOCKS5Stub(ctx *testcontext.Context, t *testing.T) *socks5Stub {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	stub := &socks5Stub{listener: listener}
+	ctx.Go(func() error {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return nil
+			}
+			ctx.Go(func() error {
+				defer func() { _ = conn.Close() }()
+				stub.handle(conn)
+				return nil
+			})
+		}
+	})
+	return stub
+}
+
+func (stub *socks5Stub) Targets() []string {
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	return append([]string(nil), stub.targets...)
+}
+
+func (stub *socks5Stub) handle(conn net.Conn) {
+	var greeting [2]byte
+	if _, err := io.ReadFull(conn, greeting[:]); err != nil {
+		return
+	}
+	methods := make([]byte, greeting[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return
+	}
+	if _, err := conn.Write([]byte{socks5Version, socks5AuthNone}); err != nil {
+		return
+	}
+
+	var request [4]byte
+	if _, err := io.ReadFull(conn, request[:]); err != nil {
+		return
+	}
+	if request[3] != socks5AddrIPv4 {
+		return
+	}
+	var addr [net.IPv4len + 2]byte
+	if _, err := io.ReadFull(conn, addr[:]); err != nil {
+		return
+	}
+	port := int(addr[4])<<8 | int(addr[5])
+	target := net.JoinHostPort(net.IP(addr[:4]).String(), strconv.Itoa(port))
+
+	stub.mu.Lock()
+	stub.targets = append(stub.targets, target)
+	stub.mu.Unlock()
+
+	upstream, err := net.Dial("tcp", target)
+	if err != nil {
+		_, _ = conn.Write([]byte{socks5Version, 1, 0, socks5AddrIPv4, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer func() { _ = upstream.Close() }()
+
+	if _, err := conn.Write([]byte{socks5Version, socks5ReplySucceeded, 0, socks5AddrIPv4, 0, 0, 0, 0, 0, 0}); err != nil {
+		return
+	}
+
+	go func() {
+		_, _ = io.Copy(upstream, conn)
+		_ = upstream.Close()
+	}()
+	_, _ = io.Copy(conn, upstream)
+}
+
+func TestConnectorWithProxy_SOCKS5(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	serverOpts := newTestTLSOptions(t, 0)
+	clientOpts := newTestTLSOptions(t, 1)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ctx.Check(listener.Close)
+	serveTestTLS(ctx, listener, serverOpts)
+
+	stub := newSOCKS5Stub(ctx, t)
+	defer ctx.Check(stub.listener.Close)
+
+	connector, err := ConnectorWithProxy(&url.URL{Scheme: "socks5", Host: stub.listener.Addr().String()})
+	require.NoError(t, err)
+
+	conn, err := connector.DialContext(ctx, clientOpts.ClientTLSConfig(serverOpts.Ident.ID), listener.Addr().String())
+	require.NoError(t, err)
+
+	_, err = conn.Write([]byte("hello"))
+	require.NoError(t, err)
+	data := make([]byte, 5)
+	_, err = io.ReadFull(conn, data)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	require.NoError(t, conn.Close())
+
+	assert.Equal(t, []string{listener.Addr().String()}, stub.Targets())
+
+	// tls verification still uses the target node id
+	wrongID := newTestTLSOptions(t, 2).Ident.ID
+	_, err = connector.DialContext(ctx, clientOpts.ClientTLSConfig(wrongID), listener.Addr().String())
+	require.Error(t, err)
+}
+
+func TestConnectorWithProxy_HTTP(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	target, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ctx.Check(target.Close)
+	ctx.Go(func() error {
+		conn, err := target.Accept()
+		if err != nil {
+			return nil
+		}
+		defer func() { _ = conn.Close() }()
+		_, _ = io.Copy(conn, conn)
+		return nil
+	})
+
+	proxy, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ctx.Check(proxy.Close)
+
+	requested := make(chan string, 1)
+	ctx.Go(func() error {
+		conn, err := proxy.Accept()
+		if err != nil {
+			return nil
+		}
+		defer func() { _ = conn.Close() }()
+
+		request, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil {
+			return err
+		}
+		requested <- request.Method + " " + request.Host + " " + request.Header.Get("Proxy-Authorization")
+
+		upstream, err := net.Dial("tcp", request.Host)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = upstream.Close() }()
+
+		if _, err := io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n"); err != nil {
+			return err
+		}
+		go func() {
+			_, _ = io.Copy(upstream, conn)
+			_ = upstream.Close()
+		}()
+		_, _ = io.Copy(conn, upstream)
+		return nil
+	})
+
+	adapter, err := NewProxyConnectorAdapter(&url.URL{
+		Scheme: "http",
+		Host:   proxy.Addr().String(),
+		User:   url.UserPassword("user", "pass"),
+	})
+	require.NoError(t, err)
+
+	conn, err := adapter.DialContext(ctx, "tcp", target.Addr().String())
+	require.NoError(t, err)
+	defer ctx.Check(conn.Close)
+
+	assert.Equal(t, "CONNECT "+target.Addr().String()+" Basic dXNlcjpwYXNz", <-requested)
+
+	_, err = conn.Write([]byte("hello"))
+	require.NoError(t, err)
+	data := make([]byte, 5)
+	_, err = io.ReadFull(conn, data)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+}
+
+func TestConnectorWithProxy_Invalid(t *testing.T) {
+	_, err := ConnectorWithProxy(nil)
+	require.Error(t, err)
+
+	_, err = ConnectorWithProxy(&url.URL{Scheme: "ftp", Host: "localhost"})
+	require.Error(t, err)
+
+	ctx := context.Background()
+	adapter, err := NewProxyConnectorAdapter(&url.URL{Scheme: "socks5", Host: "localhost"})
+	require.NoError(t, err)
+	_, err = adapter.DialContext(ctx, "udp", "localhost:1")
+	require.Error(t, err)
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package rpctimeout
+
+import (
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/drpc"
+)
+
+// ErrMessageTimeout is returned when a single message on a stream does not
+// complete within the timeout.
+var ErrMessageTimeout = errs.Class("message timeout")
+
+// Stream wraps a drpc.Stream so that every MsgSend and MsgRecv has to complete
+// within the timeout. Slow messages that complete in time do not affect the
+// stream. When a message times out, or the stream context is done while it is
+// in flight, the stream is closed, since the message can not be abandoned
+// without leaving the stream in an unknown state.
+type Stream struct {
+	drpc.Stream
+	timeout time.Duration
+}
+
+// NewStream returns a Stream that enforces timeout on every message of stream.
+// The timeout is capped at the deadline of the stream context. A message that
+// times out closes the whole stream, not just the message.
+func NewStream(stream drpc.Stream, timeout time.Duration) *Stream {
+	return &Stream{Stream: stream, timeout: timeout}
+}
+
+// MsgSend sends the Message to the remote.
+func (s *Stream) MsgSend(msg drpc.Message) error {
+	return s.run(func() error { return s.Stream.MsgSend(msg) })
+}
+
+// MsgRecv receives a Message from the remote.
+func (s *Stream) MsgRecv(msg drpc.Message) error {
+	return s.run(func() error { return s.Stream.MsgRecv(msg) })
+}
+
+// run calls fn and closes the stream when it does not return within the timeout
+// or before the stream context is done. It always waits for fn to return, so msg
+// is not used after returning.
+func (s *Stream) run(fn func() error) error {
+	ctx := s.Stream.Context()
+
+	timeout := s.timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		if untilDeadline := time.Until(deadline); untilDeadline < timeout {
+			timeout = untilDeadline
+		}
+	}
+
+	errch := make(chan error, 1)
+	go func() { errch <- fn() }()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-errch:
+		return err
+	case <-ctx.Done():
+		closeErr := s.Stream.Close()
+		<-errch
+		return errs.Combine(ctx.Err(), closeErr)
+	case <-timer.C:
+		closeErr := s.Stream.Close()
+		<-errch
+		return errs.Combine(ErrMessageTimeout.New("did not complete within %v", timeout), closeErr)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package rpctimeout_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/rpc/rpctimeout"
+	"storj.io/common/testcontext"
+	"storj.io/drpc"
+)
+
+// stubStream is a drpc.Stream with a configurable delay for every received message.
+// Received messages only stop early when the stream is closed, not when its
+// context is done.
+type stubStream struct {
+	ctx    context.Context
+	cancel func()
+	delays []time.Duration
+	closed chan struct{}
+	once   sync.Once
+
+	mu   sync.Mutex
+	recv int
+}
+
+func newStubStream(ctx context.Context, delays ...time.Duration) *stubStream {
+	ctx, cancel := context.WithCancel(ctx)
+	return &stubStream{ctx: ctx, cancel: cancel, delays: delays, closed: make(chan struct{})}
+}
+
+func (s *stubStream) Context() context.Context   { return s.ctx }
+func (s *stubStream) MsgSend(drpc.Message) error { return s.ctx.Err() }
+func (s *stubStream) CloseSend() error           { return nil }
+
+func (s *stubStream) Close() error {
+	s.once.Do(func() {
+		s.cancel()
+		close(s.closed)
+	})
+	return nil
+}
+
+func (s *stubStream) MsgRecv(msg drpc.Message) error {
+	s.mu.Lock()
+	delay := s.delays[s.recv]
+	s.recv++
+	s.mu.Unlock()
+
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-s.closed:
+		return errors.New("stream closed")
+	}
+}
+
+func TestStream(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	stub := newStubStream(ctx, 0, 50*time.Millisecond, time.Hour)
+	stream := rpctimeout.NewStream(stub, 500*time.Millisecond)
+
+	require.NoError(t, stream.MsgSend(nil))
+	require.NoError(t, stream.MsgRecv(nil))
+
+	// slow but completing messages don't affect the stream
+	require.NoError(t, stream.MsgRecv(nil))
+	require.NoError(t, stub.Context().Err())
+
+	// a stalled message times out and closes the stream
+	err := stream.MsgRecv(nil)
+	require.Error(t, err)
+	assert.True(t, rpctimeout.ErrMessageTimeout.Has(err))
+	assert.Error(t, stub.Context().Err())
+}
+
+func TestStream_ContextCanceled(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	stub := newStubStream(canceled, time.Hour)
+	stream := rpctimeout.NewStream(stub, time.Hour)
+
+	// a stalled message on a canceled stream returns without waiting for the timeout
+	start := time.Now()
+	err := stream.MsgRecv(nil)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.False(t, rpctimeout.ErrMessageTimeout.Has(err))
+	assert.True(t, time.Since(start) < time.Minute)
+}
+
+func TestStream_ContextDeadline(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	deadlineCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	stub := newStubStream(deadlineCtx, time.Hour)
+	stream := rpctimeout.NewStream(stub, time.Hour)
+
+	// the timeout is capped at the deadline of the stream context
+	start := time.Now()
+	require.Error(t, stream.MsgRecv(nil))
+	assert.True(t, time.Since(start) < time.Minute)
+}
@@ -4,9 +4,16 @@
 package storj
 
 import (
+	"crypto/sha256"
 	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
 
 	"github.com/zeebo/errs"
+	"golang.org/x/crypto/hkdf"
 )
 
 // EncryptionParameters is the cipher suite and parameters used for encryption.
@@ -29,6 +36,46 @@ func (params EncryptionParameters) IsZero() bool {
 	return params == (EncryptionParameters{})
 }
 
+// ErrEncryptionParameters is used when something goes wrong with encryption parameters.
+var ErrEncryptionParameters = errs.Class("encryption parameters error")
+
+const (
+	// encryptionParametersVersion1 is the version byte of the binary encoding
+	// of EncryptionParameters.
+	encryptionParametersVersion1 = 1
+	// encryptionParametersSize1 is the length of version 1 of the binary
+	// encoding: version, cipher suite and big-endian block size.
+	encryptionParametersSize1 = 1 + 1 + 4
+)
+
+// MarshalBinary encodes the parameters as a version byte, the cipher suite
+// byte and the block size as a big-endian int32.
+func (params EncryptionParameters) MarshalBinary() ([]byte, error) {
+	data := make([]byte, encryptionParametersSize1)
+	data[0] = encryptionParametersVersion1
+	data[1] = byte(params.CipherSuite)
+	binary.BigEndian.PutUint32(data[2:], uint32(params.BlockSize))
+	return data, nil
+}
+
+// UnmarshalBinary decodes parameters encoded by MarshalBinary.
+func (params *EncryptionParameters) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return ErrEncryptionParameters.New("no data")
+	}
+	if data[0] != encryptionParametersVersion1 {
+		return ErrEncryptionParameters.New("unknown version %d", data[0])
+	}
+	if len(data) != encryptionParametersSize1 {
+		return ErrEncryptionParameters.New("invalid length; have %d, need %d", len(data), encryptionParametersSize1)
+	}
+	*params = EncryptionParameters{
+		CipherSuite: CipherSuite(data[1]),
+		BlockSize:   int32(binary.BigEndian.Uint32(data[2:])),
+	}
+	return nil
+}
+
 // CipherSuite specifies one of the encryption suites supported by Storj
 // libraries for encryption of in-network data.
 type CipherSuite byte
@@ -49,6 +96,44 @@ const (
 	EncNullBase64URL
 )
 
+// AllCipherSuites returns the cipher suites that can be selected for
+// encryption, in a stable order. EncUnspecified and the internal
+// EncNullBase64URL are not included.
+func AllCipherSuites() []CipherSuite {
+	return []CipherSuite{EncNull, EncAESGCM, EncSecretBox}
+}
+
+// IsValid returns whether the cipher suite is one of AllCipherSuites.
+func (cipher CipherSuite) IsValid() bool {
+	switch cipher {
+	case EncNull, EncAESGCM, EncSecretBox:
+		return true
+	}
+	return false
+}
+
+// NonceSize returns the size of the nonce used by the cipher suite. It is 0
+// for suites that do not encrypt.
+func (cipher CipherSuite) NonceSize() int {
+	switch cipher {
+	case EncAESGCM:
+		return 12
+	case EncSecretBox:
+		return NonceSize
+	}
+	return 0
+}
+
+// Overhead returns the number of bytes the cipher suite's authentication tag
+// adds to each encrypted block. It is 0 for suites that do not encrypt.
+func (cipher CipherSuite) Overhead() int {
+	switch cipher {
+	case EncAESGCM, EncSecretBox:
+		return 16
+	}
+	return 0
+}
+
 // Constant definitions for key and nonce sizes.
 const (
 	KeySize   = 32
@@ -69,6 +154,20 @@ func NewKey(humanReadableKey []byte) (*Key, error) {
 // Key represents the largest key used by any encryption protocol.
 type Key [KeySize]byte
 
+// ErrKey is used when something goes wrong with a key.
+var ErrKey = errs.Class("key error")
+
+// DeriveKey derives a new key from master using HKDF-SHA256 with an empty
+// salt. Different info labels yield independent keys.
+func DeriveKey(master Key, info []byte) (Key, error) {
+	var derived Key
+	_, err := io.ReadFull(hkdf.New(sha256.New, master[:], nil, info), derived[:])
+	if err != nil {
+		return Key{}, ErrKey.Wrap(err)
+	}
+	return derived, nil
+}
+
 // Raw returns the key as a raw byte array pointer.
 func (key *Key) Raw() *[KeySize]byte {
 	return (*[KeySize]byte)(key)
@@ -79,6 +178,19 @@ func (key *Key) IsZero() bool {
 	return key == nil || *key == (Key{})
 }
 
+// String returns a redacted form of the key, so that logging it doesn't leak
+// the secret. Use Hex when the raw value is needed.
+func (key Key) String() string {
+	return fmt.Sprintf("Key(redacted, %d bytes)", len(key))
+}
+
+// GoString returns the same redacted form as String, so that %#v doesn't leak
+// the secret either.
+func (key Key) GoString() string { return key.String() }
+
+// Hex returns the raw key hex encoded.
+func (key Key) Hex() string { return hex.EncodeToString(key[:]) }
+
 // ErrNonce is used when something goes wrong with a stream ID.
 var ErrNonce = errs.Class("nonce error")
 
@@ -97,6 +209,15 @@ func NonceFromString(s string) (Nonce, error) {
 	return NonceFromBytes(nonceBytes)
 }
 
+// NonceFromBase64 decodes a nonce encoded with Base64.
+func NonceFromBase64(s string) (Nonce, error) {
+	nonceBytes, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return Nonce{}, ErrNonce.Wrap(err)
+	}
+	return NonceFromBytes(nonceBytes)
+}
+
 // NonceFromBytes converts a byte slice into a nonce.
 func NonceFromBytes(b []byte) (Nonce, error) {
 	if len(b) != len(Nonce{}) {
@@ -113,9 +234,24 @@ func (nonce Nonce) IsZero() bool {
 	return nonce == Nonce{}
 }
 
+// XOR returns the byte-wise XOR of the nonce and other.
+func (nonce Nonce) XOR(other Nonce) Nonce {
+	var result Nonce
+	for i := range result {
+		result[i] = nonce[i] ^ other[i]
+	}
+	return result
+}
+
 // String representation of the nonce.
 func (nonce Nonce) String() string { return nonceEncoding.EncodeToString(nonce.Bytes()) }
 
+// Hex returns the nonce hex encoded.
+func (nonce Nonce) Hex() string { return hex.EncodeToString(nonce[:]) }
+
+// Base64 returns the nonce encoded with standard base64.
+func (nonce Nonce) Base64() string { return base64.StdEncoding.EncodeToString(nonce[:]) }
+
 // Bytes returns bytes of the nonce.
 func (nonce Nonce) Bytes() []byte { return nonce[:] }
 
@@ -152,6 +288,19 @@ func (nonce Nonce) MarshalJSON() ([]byte, error) {
 	return []byte(`"` + nonce.String() + `"`), nil
 }
 
+// MarshalText serializes a nonce to the same form as String, so that it
+// matches the JSON value and log form.
+func (nonce Nonce) MarshalText() ([]byte, error) {
+	return []byte(nonce.String()), nil
+}
+
+// UnmarshalText deserializes a nonce from the form returned by String.
+func (nonce *Nonce) UnmarshalText(text []byte) error {
+	var err error
+	*nonce, err = NonceFromString(string(text))
+	return err
+}
+
 // UnmarshalJSON deserializes a json string (as bytes) to a nonce.
 func (nonce *Nonce) UnmarshalJSON(data []byte) error {
 	var err error
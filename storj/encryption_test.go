@@ -4,6 +4,12 @@
 package storj_test
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -96,3 +102,176 @@ func TestKey_IsZero(t *testing.T) {
 		require.False(t, key.IsZero())
 	})
 }
+
+func TestKey_String(t *testing.T) {
+	key := testrand.Key()
+	encoded := hex.EncodeToString(key[:])
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%x", "%X", "%q"} {
+		for _, value := range []interface{}{key, &key} {
+			printed := fmt.Sprintf(format, value)
+			assert.NotContains(t, strings.ToLower(printed), encoded, format)
+			assert.NotContains(t, printed, string(key[:]), format)
+		}
+	}
+	assert.Equal(t, "Key(redacted, 32 bytes)", key.String())
+	assert.Equal(t, key.String(), fmt.Sprintf("%v", key))
+	assert.Equal(t, key.String(), fmt.Sprintf("%#v", &key))
+
+	assert.Equal(t, encoded, key.Hex())
+}
+
+func TestNonce_Hex(t *testing.T) {
+	nonce := testrand.Nonce()
+	assert.Equal(t, hex.EncodeToString(nonce[:]), nonce.Hex())
+
+	// String is the encoding used by NonceFromString
+	parsed, err := storj.NonceFromString(nonce.String())
+	require.NoError(t, err)
+	assert.Equal(t, nonce, parsed)
+}
+
+func TestNonce_Base64(t *testing.T) {
+	nonce := testrand.Nonce()
+
+	parsed, err := storj.NonceFromBase64(nonce.Base64())
+	require.NoError(t, err)
+	assert.Equal(t, nonce, parsed)
+
+	_, err = storj.NonceFromBase64(base64.StdEncoding.EncodeToString(nonce[:storj.NonceSize-1]))
+	require.True(t, storj.ErrNonce.Has(err), err)
+	_, err = storj.NonceFromBase64(base64.StdEncoding.EncodeToString(append(nonce[:], 0)))
+	require.True(t, storj.ErrNonce.Has(err), err)
+	_, err = storj.NonceFromBase64("not base64!")
+	require.True(t, storj.ErrNonce.Has(err), err)
+}
+
+func TestNonce_Text(t *testing.T) {
+	nonce := testrand.Nonce()
+
+	text, err := nonce.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, nonce.String(), string(text))
+
+	var unmarshaled storj.Nonce
+	require.NoError(t, unmarshaled.UnmarshalText(text))
+	assert.Equal(t, nonce, unmarshaled)
+	require.Error(t, unmarshaled.UnmarshalText([]byte(nonce.Base64())))
+
+	// map keys use the text form, which matches the json value
+	data, err := json.Marshal(map[storj.Nonce]storj.Nonce{nonce: nonce})
+	require.NoError(t, err)
+	assert.Equal(t, `{"`+nonce.String()+`":"`+nonce.String()+`"}`, string(data))
+
+	var keys map[storj.Nonce]int
+	require.NoError(t, json.Unmarshal([]byte(`{"`+nonce.String()+`":1}`), &keys))
+	assert.Equal(t, map[storj.Nonce]int{nonce: 1}, keys)
+}
+
+func TestCipherSuite_IsValid(t *testing.T) {
+	suites := storj.AllCipherSuites()
+	require.Equal(t, []storj.CipherSuite{storj.EncNull, storj.EncAESGCM, storj.EncSecretBox}, suites)
+
+	for _, cipher := range suites {
+		assert.True(t, cipher.IsValid(), cipher)
+	}
+	assert.False(t, storj.EncUnspecified.IsValid())
+	assert.False(t, storj.EncNullBase64URL.IsValid())
+	assert.False(t, storj.CipherSuite(255).IsValid())
+}
+
+func TestCipherSuite_Sizes(t *testing.T) {
+	for _, test := range []struct {
+		cipher    storj.CipherSuite
+		nonceSize int
+		overhead  int
+	}{
+		{storj.EncNull, 0, 0},
+		{storj.EncNullBase64URL, 0, 0},
+		{storj.EncUnspecified, 0, 0},
+		{storj.EncAESGCM, 12, 16},
+		{storj.EncSecretBox, 24, 16},
+	} {
+		assert.Equal(t, test.nonceSize, test.cipher.NonceSize(), test.cipher)
+		assert.Equal(t, test.overhead, test.cipher.Overhead(), test.cipher)
+	}
+}
+
+func TestNonce_XOR(t *testing.T) {
+	a, b := testrand.Nonce(), testrand.Nonce()
+
+	require.Equal(t, a, a.XOR(b).XOR(b))
+	require.Equal(t, a.XOR(b), b.XOR(a))
+	require.True(t, a.XOR(a).IsZero())
+	require.Equal(t, a, a.XOR(storj.Nonce{}))
+}
+
+func TestEncryptionParameters_Binary(t *testing.T) {
+	for _, params := range []storj.EncryptionParameters{
+		{},
+		{CipherSuite: storj.EncAESGCM, BlockSize: 29 * 256},
+		{CipherSuite: storj.EncSecretBox, BlockSize: math.MaxInt32},
+		{CipherSuite: storj.EncNull, BlockSize: -1},
+	} {
+		data, err := params.MarshalBinary()
+		require.NoError(t, err)
+		require.Len(t, data, 6)
+
+		var decoded storj.EncryptionParameters
+		require.NoError(t, decoded.UnmarshalBinary(data))
+		require.Equal(t, params, decoded)
+	}
+
+	// the layout is fixed
+	data, err := storj.EncryptionParameters{CipherSuite: storj.EncAESGCM, BlockSize: 0x01020304}.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, byte(storj.EncAESGCM), 1, 2, 3, 4}, data)
+}
+
+func TestEncryptionParameters_UnmarshalBinaryErrors(t *testing.T) {
+	for _, data := range [][]byte{
+		nil,
+		{0, byte(storj.EncAESGCM), 0, 0, 1, 0},
+		{2, byte(storj.EncAESGCM), 0, 0, 1, 0},
+		{1, byte(storj.EncAESGCM), 0, 0, 1},
+		{1, byte(storj.EncAESGCM), 0, 0, 1, 0, 0},
+	} {
+		params := storj.EncryptionParameters{CipherSuite: storj.EncNull, BlockSize: 1}
+		err := params.UnmarshalBinary(data)
+		require.Error(t, err, data)
+		require.True(t, storj.ErrEncryptionParameters.Has(err), data)
+		require.Equal(t, storj.EncryptionParameters{CipherSuite: storj.EncNull, BlockSize: 1}, params)
+	}
+}
+
+func TestDeriveKey(t *testing.T) {
+	var master storj.Key
+	for i := range master {
+		master[i] = byte(i)
+	}
+
+	for _, tt := range []struct {
+		master   storj.Key
+		info     string
+		expected string
+	}{
+		{master, "metadata", "380ad38d1736f8188f1e0ca7c5fc8859f7055ca7f3d3f6e4f301bce954f71d95"},
+		{master, "", "37ad29109f43265287804b674e2653d0a513718907f97fca97c95bded8104bbf"},
+		{storj.Key{}, "metadata", "348881de9f9f7874c9f85a3897be8188c726e10c9484fd798e84099e28e41aa4"},
+	} {
+		derived, err := storj.DeriveKey(tt.master, []byte(tt.info))
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, hex.EncodeToString(derived[:]), tt.info)
+	}
+
+	a, err := storj.DeriveKey(master, []byte("a"))
+	require.NoError(t, err)
+	b, err := storj.DeriveKey(master, []byte("b"))
+	require.NoError(t, err)
+	again, err := storj.DeriveKey(master, []byte("a"))
+	require.NoError(t, err)
+
+	assert.NotEqual(t, a, b)
+	assert.NotEqual(t, master, a)
+	assert.Equal(t, a, again)
+}
@@ -4,6 +4,7 @@
 package storj
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"crypto/x509/pkix"
 	"database/sql/driver"
@@ -122,6 +123,41 @@ func (id NodeID) Less(other NodeID) bool {
 	return false
 }
 
+// Prefix returns the first n bits of the id. The bits past the prefix in the
+// last byte are zero. n is clamped to the size of the id.
+func (id NodeID) Prefix(n int) []byte {
+	if n <= 0 {
+		return []byte{}
+	}
+	if n > len(id)*8 {
+		n = len(id) * 8
+	}
+	prefix := make([]byte, (n+7)/8)
+	copy(prefix, id[:])
+	if extra := n % 8; extra != 0 {
+		prefix[len(prefix)-1] &= 0xFF << (8 - extra)
+	}
+	return prefix
+}
+
+// HasPrefix returns whether the first n bits of the id are the same as the
+// first n bits of prefix. It returns false when n is larger than the prefix
+// or the id.
+func (id NodeID) HasPrefix(prefix []byte, n int) bool {
+	if n < 0 || n > len(prefix)*8 || n > len(id)*8 {
+		return false
+	}
+	full := n / 8
+	if !bytes.Equal(id[:full], prefix[:full]) {
+		return false
+	}
+	if extra := n % 8; extra != 0 {
+		mask := byte(0xFF << (8 - extra))
+		return id[full]&mask == prefix[full]&mask
+	}
+	return true
+}
+
 // Version returns the version of the identity format.
 func (id NodeID) Version() IDVersion {
 	versionNumber := id.versionByte()
@@ -207,13 +243,18 @@ func (id NodeID) Value() (driver.Value, error) {
 	return id.Bytes(), nil
 }
 
-// Scan extracts a NodeID from a database field.
+// Scan extracts a NodeID from a database field. It accepts the raw bytes
+// or the string form of the NodeID.
 func (id *NodeID) Scan(src interface{}) (err error) {
-	b, ok := src.([]byte)
-	if !ok {
-		return ErrNodeID.New("NodeID Scan expects []byte")
+	var n NodeID
+	switch src := src.(type) {
+	case []byte:
+		n, err = NodeIDFromBytes(src)
+	case string:
+		n, err = NodeIDFromString(src)
+	default:
+		return ErrNodeID.New("NodeID Scan expects []byte or string")
 	}
-	n, err := NodeIDFromBytes(b)
 	*id = n
 	return err
 }
@@ -64,6 +64,23 @@ func TestNodeScan(t *testing.T) {
 	require.Error(t, tmpID.Scan(false))
 	require.Error(t, tmpID.Scan([]byte{}))
 	require.NoError(t, tmpID.Scan(tmpID.Bytes()))
+
+	id := testrand.NodeID()
+	value, err := id.Value()
+	require.NoError(t, err)
+
+	var scanned storj.NodeID
+	require.NoError(t, scanned.Scan(value))
+	require.Equal(t, id, scanned)
+
+	var fromString storj.NodeID
+	require.NoError(t, fromString.Scan(id.String()))
+	require.Equal(t, id, fromString)
+
+	err = scanned.Scan(id.Bytes()[:storj.NodeIDSize-1])
+	require.Error(t, err)
+	require.True(t, storj.ErrNodeID.Has(err))
+	require.Error(t, scanned.Scan("invalid"))
 }
 
 // TestNodeValue tests NodeID.Value().
@@ -190,3 +207,47 @@ func TestNodeIDList_Contains(t *testing.T) {
 		assert.Equal(t, testcase.contains, testcase.list.Contains(testcase.id))
 	}
 }
+
+func TestNodeID_Prefix(t *testing.T) {
+	id := storj.NodeID{0xAB, 0xCD, 0xEF}
+
+	assert.Equal(t, []byte{}, id.Prefix(0))
+	assert.Equal(t, []byte{0x80}, id.Prefix(1))
+	assert.Equal(t, []byte{0xAB}, id.Prefix(8))
+	assert.Equal(t, []byte{0xAB, 0xC0}, id.Prefix(12))
+	assert.Equal(t, []byte{0xAB, 0xCD, 0xEE}, id.Prefix(23))
+	assert.Equal(t, id.Bytes(), id.Prefix(len(id)*8))
+	assert.Equal(t, id.Bytes(), id.Prefix(len(id)*8+5))
+
+	// all ids sharing the first 12 bits match, regardless of the rest
+	prefix := id.Prefix(12)
+	for _, other := range []storj.NodeID{
+		{0xAB, 0xC0},
+		{0xAB, 0xCF, 0xFF},
+		{0xAB, 0xC7, 0x12, 0x34},
+	} {
+		assert.True(t, other.HasPrefix(prefix, 12), other)
+		assert.True(t, id.HasPrefix(other.Prefix(12), 12), other)
+	}
+	for _, other := range []storj.NodeID{
+		{0xAB, 0xD0},
+		{0xAB, 0xBF, 0xFF},
+		{0xBB, 0xC0},
+		{},
+	} {
+		assert.False(t, other.HasPrefix(prefix, 12), other)
+	}
+
+	// bits past the prefix are ignored
+	assert.True(t, id.HasPrefix([]byte{0xAB, 0xCF}, 12))
+	assert.True(t, id.HasPrefix([]byte{}, 0))
+	assert.False(t, id.HasPrefix([]byte{0xAB}, 12))
+	assert.False(t, id.HasPrefix(prefix, -1))
+
+	for i := 0; i < 10; i++ {
+		random := testrand.NodeID()
+		for _, n := range []int{0, 3, 12, 64, 255, 256} {
+			assert.True(t, random.HasPrefix(random.Prefix(n), n))
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storj
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// NodeURLResolver resolves the hosts of node urls and caches the results.
+type NodeURLResolver struct {
+	lookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
+	now          func() time.Time
+
+	mu    sync.Mutex
+	hosts map[string]resolvedHost
+}
+
+// resolvedHost is a cached result of resolving a host.
+type resolvedHost struct {
+	ip      string
+	expires time.Time
+}
+
+// NewNodeURLResolver returns a NodeURLResolver that resolves hosts with
+// lookupIPAddr. If lookupIPAddr is nil, net.DefaultResolver is used.
+func NewNodeURLResolver(lookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)) *NodeURLResolver {
+	if lookupIPAddr == nil {
+		lookupIPAddr = net.DefaultResolver.LookupIPAddr
+	}
+	return &NodeURLResolver{
+		lookupIPAddr: lookupIPAddr,
+		now:          time.Now,
+		hosts:        map[string]resolvedHost{},
+	}
+}
+
+// defaultNodeURLResolver is the resolver used by ResolveNodeURL.
+var defaultNodeURLResolver = NewNodeURLResolver(nil)
+
+// ResolveNodeURL resolves the url with a shared NodeURLResolver that uses
+// net.DefaultResolver.
+func ResolveNodeURL(ctx context.Context, url NodeURL, cacheTTL time.Duration) (NodeURL, error) {
+	return defaultNodeURLResolver.Resolve(ctx, url, cacheTTL)
+}
+
+// Resolve resolves the host of the url address to an ip address and returns
+// the url with the ip substituted. The node id is kept, so it can be used to
+// verify the node. Resolved hosts are cached for cacheTTL, a non-positive
+// cacheTTL disables caching.
+func (resolver *NodeURLResolver) Resolve(ctx context.Context, url NodeURL, cacheTTL time.Duration) (NodeURL, error) {
+	host, port, err := net.SplitHostPort(url.Address)
+	if err != nil {
+		return NodeURL{}, ErrNodeURL.Wrap(err)
+	}
+	if net.ParseIP(host) != nil {
+		return url, nil
+	}
+
+	if cacheTTL > 0 {
+		if ip, ok := resolver.cached(host); ok {
+			url.Address = net.JoinHostPort(ip, port)
+			return url, nil
+		}
+	}
+
+	addrs, err := resolver.lookupIPAddr(ctx, host)
+	if err != nil {
+		return NodeURL{}, ErrNodeURL.Wrap(err)
+	}
+	if len(addrs) == 0 {
+		return NodeURL{}, ErrNodeURL.New("no addresses found for %q", host)
+	}
+	ip := addrs[0].IP.String()
+
+	if cacheTTL > 0 {
+		resolver.store(host, ip, cacheTTL)
+	}
+
+	url.Address = net.JoinHostPort(ip, port)
+	return url, nil
+}
+
+// cached returns the cached ip for the host, if it has not expired.
+func (resolver *NodeURLResolver) cached(host string) (string, bool) {
+	resolver.mu.Lock()
+	defer resolver.mu.Unlock()
+
+	entry, ok := resolver.hosts[host]
+	if !ok {
+		return "", false
+	}
+	if !resolver.now().Before(entry.expires) {
+		delete(resolver.hosts, host)
+		return "", false
+	}
+	return entry.ip, true
+}
+
+// store caches the ip for the host and drops the expired entries.
+func (resolver *NodeURLResolver) store(host, ip string, cacheTTL time.Duration) {
+	resolver.mu.Lock()
+	defer resolver.mu.Unlock()
+
+	now := resolver.now()
+	for name, entry := range resolver.hosts {
+		if !now.Before(entry.expires) {
+			delete(resolver.hosts, name)
+		}
+	}
+	resolver.hosts[host] = resolvedHost{ip: ip, expires: now.Add(cacheTTL)}
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storj
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubResolver returns a NodeURLResolver with a fake clock and a lookup
+// function that counts its calls.
+func stubResolver(lookups *int, now *time.Time) *NodeURLResolver {
+	resolver := NewNodeURLResolver(func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		*lookups++
+		switch host {
+		case "satellite.test", "other.test":
+			return []net.IPAddr{{IP: net.ParseIP("10.0.0.1")}}, nil
+		case "ipv6.test":
+			return []net.IPAddr{{IP: net.ParseIP("2001:db8::1")}}, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	})
+	resolver.now = func() time.Time { return *now }
+	return resolver
+}
+
+func TestNodeURLResolver(t *testing.T) {
+	ctx := context.Background()
+
+	lookups, now := 0, time.Now()
+	resolver := stubResolver(&lookups, &now)
+
+	id := NodeID{1, 2, 3}
+
+	resolved, err := resolver.Resolve(ctx, NodeURL{ID: id, Address: "satellite.test:7777"}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, NodeURL{ID: id, Address: "10.0.0.1:7777"}, resolved)
+	assert.Equal(t, 1, lookups)
+
+	// second call within ttl uses the cache
+	resolved, err = resolver.Resolve(ctx, NodeURL{ID: id, Address: "satellite.test:8888"}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, NodeURL{ID: id, Address: "10.0.0.1:8888"}, resolved)
+	assert.Equal(t, 1, lookups)
+
+	// no caching with zero ttl
+	resolved, err = resolver.Resolve(ctx, NodeURL{ID: id, Address: "ipv6.test:7777"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, NodeURL{ID: id, Address: "[2001:db8::1]:7777"}, resolved)
+	_, err = resolver.Resolve(ctx, NodeURL{ID: id, Address: "ipv6.test:7777"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, lookups)
+
+	// ip addresses are not resolved
+	resolved, err = resolver.Resolve(ctx, NodeURL{ID: id, Address: "33.20.0.1:7777"}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, NodeURL{ID: id, Address: "33.20.0.1:7777"}, resolved)
+	assert.Equal(t, 3, lookups)
+
+	_, err = resolver.Resolve(ctx, NodeURL{ID: id, Address: "unknown.test:7777"}, time.Hour)
+	require.Error(t, err)
+	assert.True(t, ErrNodeURL.Has(err))
+
+	_, err = resolver.Resolve(ctx, NodeURL{ID: id, Address: "satellite.test"}, time.Hour)
+	require.Error(t, err)
+}
+
+func TestNodeURLResolver_Expiration(t *testing.T) {
+	ctx := context.Background()
+
+	lookups, now := 0, time.Now()
+	resolver := stubResolver(&lookups, &now)
+
+	_, err := resolver.Resolve(ctx, NodeURL{Address: "satellite.test:7777"}, time.Minute)
+	require.NoError(t, err)
+	_, err = resolver.Resolve(ctx, NodeURL{Address: "other.test:7777"}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 2, lookups)
+
+	now = now.Add(2 * time.Minute)
+
+	// the expired entry is resolved again
+	_, err = resolver.Resolve(ctx, NodeURL{Address: "satellite.test:7777"}, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 3, lookups)
+
+	_, err = resolver.Resolve(ctx, NodeURL{Address: "other.test:7777"}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 3, lookups)
+
+	// expired entries are dropped from the cache
+	now = now.Add(2 * time.Hour)
+	_, err = resolver.Resolve(ctx, NodeURL{Address: "ipv6.test:7777"}, time.Minute)
+	require.NoError(t, err)
+	assert.Len(t, resolver.hosts, 1)
+}
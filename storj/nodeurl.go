@@ -4,7 +4,9 @@
 package storj
 
 import (
+	"net"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/zeebo/errs"
@@ -35,10 +37,22 @@ type NodeURL struct {
 //
 //    without host:
 //      12vha9oTFnerxYRgeQ2BZqoFrLrnmmf5UWTCY2jA77dF3YvWew7@
+//      12vha9oTFnerxYRgeQ2BZqoFrLrnmmf5UWTCY2jA77dF3YvWew7
+//
+// A string without any of "@:/[." is parsed as a node id, except for
+// "localhost", and it is an error if it is not a valid one. Other host names
+// without a port need a dot, such as example.com.
 func ParseNodeURL(s string) (NodeURL, error) {
 	if s == "" {
 		return NodeURL{}, nil
 	}
+	if !strings.ContainsAny(s, "@:/[.") && s != "localhost" {
+		id, err := NodeIDFromString(s)
+		if err != nil {
+			return NodeURL{}, ErrNodeURL.New("invalid node id %q: %v", s, err)
+		}
+		return NodeURL{ID: id}, nil
+	}
 	if !strings.HasPrefix(s, "storj://") {
 		if !strings.Contains(s, "://") {
 			s = "storj://" + s
@@ -57,7 +71,7 @@ func ParseNodeURL(s string) (NodeURL, error) {
 	if u.User != nil {
 		node.ID, err = NodeIDFromString(u.User.String())
 		if err != nil {
-			return NodeURL{}, ErrNodeURL.Wrap(err)
+			return NodeURL{}, ErrNodeURL.New("invalid node id %q: %v", u.User.String(), err)
 		}
 	}
 	node.Address = u.Host
@@ -65,6 +79,24 @@ func ParseNodeURL(s string) (NodeURL, error) {
 	return node, nil
 }
 
+// ParseNodeURLWithDefaultPort parses node URL string and adds defaultPort to
+// the address when it doesn't specify a port.
+func ParseNodeURLWithDefaultPort(s string, defaultPort int) (NodeURL, error) {
+	node, err := ParseNodeURL(s)
+	if err != nil {
+		return NodeURL{}, err
+	}
+	if node.Address == "" {
+		return node, nil
+	}
+
+	if _, _, err := net.SplitHostPort(node.Address); err != nil {
+		host := strings.TrimSuffix(strings.TrimPrefix(node.Address, "["), "]")
+		node.Address = net.JoinHostPort(host, strconv.Itoa(defaultPort))
+	}
+	return node, nil
+}
+
 // IsZero returns whether the url is empty.
 func (url NodeURL) IsZero() bool {
 	return url == NodeURL{}
@@ -58,6 +58,50 @@ func TestNodeURL(t *testing.T) {
 			_, err := storj.ParseNodeURL(testcase)
 			assert.Error(t, err, testcase)
 		}
+
+		_, err := storj.ParseNodeURL("12vha9oTFnerxgeQ2BZqoFrLrnmmf5UWTCY2jA77dF3YvWew7@33.20.0.1:7777")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "12vha9oTFnerxgeQ2BZqoFrLrnmmf5UWTCY2jA77dF3YvWew7")
+
+		// a bare token is a node id and not a host
+		for _, token := range []string{"notanid", "12vha9oTFnerxgeQ2BZqoFrLrnmmf5UWTCY2jA77dF3YvWew7"} {
+			_, err = storj.ParseNodeURL(token)
+			require.Error(t, err, token)
+			assert.True(t, storj.ErrNodeURL.Has(err), token)
+			assert.Contains(t, err.Error(), token)
+		}
+	})
+
+	t.Run("IDOnly", func(t *testing.T) {
+		url, err := storj.ParseNodeURL("12vha9oTFnerxYRgeQ2BZqoFrLrnmmf5UWTCY2jA77dF3YvWew7")
+		require.NoError(t, err)
+		assert.Equal(t, storj.NodeURL{id, ""}, url)
+		assert.Equal(t, "12vha9oTFnerxYRgeQ2BZqoFrLrnmmf5UWTCY2jA77dF3YvWew7@", url.String())
+	})
+
+	t.Run("DefaultPort", func(t *testing.T) {
+		type Test struct {
+			String   string
+			Expected storj.NodeURL
+		}
+
+		for _, testcase := range []Test{
+			{"", storj.NodeURL{}},
+			{"33.20.0.1", storj.NodeURL{emptyID, "33.20.0.1:7777"}},
+			{"localhost", storj.NodeURL{emptyID, "localhost:7777"}},
+			{"example.com", storj.NodeURL{emptyID, "example.com:7777"}},
+			{"33.20.0.1:1234", storj.NodeURL{emptyID, "33.20.0.1:1234"}},
+			{"[2001:db8:1f70::999:de8:7648:6e8]", storj.NodeURL{emptyID, "[2001:db8:1f70::999:de8:7648:6e8]:7777"}},
+			{"12vha9oTFnerxYRgeQ2BZqoFrLrnmmf5UWTCY2jA77dF3YvWew7@example.com", storj.NodeURL{id, "example.com:7777"}},
+			{"12vha9oTFnerxYRgeQ2BZqoFrLrnmmf5UWTCY2jA77dF3YvWew7@", storj.NodeURL{id, ""}},
+		} {
+			url, err := storj.ParseNodeURLWithDefaultPort(testcase.String, 7777)
+			require.NoError(t, err, testcase.String)
+			assert.Equal(t, testcase.Expected, url, testcase.String)
+		}
+
+		_, err := storj.ParseNodeURLWithDefaultPort("exampl e.com", 7777)
+		require.Error(t, err)
 	})
 }
 
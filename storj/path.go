@@ -19,3 +19,21 @@ func SplitPath(path Path) []string {
 func JoinPaths(paths ...Path) Path {
 	return strings.Join(paths, "/")
 }
+
+// SplitBucketPath splits a "bucket/key" path on the first separator only,
+// so that keys containing separators are kept intact.
+func SplitBucketPath(raw string) (bucket string, key Path) {
+	if i := strings.IndexByte(raw, '/'); i >= 0 {
+		return raw[:i], raw[i+1:]
+	}
+	return raw, ""
+}
+
+// JoinBucketPath joins bucket and key into a "bucket/key" path. It is the
+// inverse of SplitBucketPath.
+func JoinBucketPath(bucket string, key Path) string {
+	if key == "" {
+		return bucket
+	}
+	return bucket + "/" + key
+}
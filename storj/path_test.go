@@ -52,3 +52,42 @@ func TestJoinPaths(t *testing.T) {
 		assert.Equal(t, tt.path, JoinPaths(tt.comps...), errTag)
 	}
 }
+
+func TestSplitBucketPath(t *testing.T) {
+	for i, tt := range []struct {
+		raw    string
+		bucket string
+		key    string
+	}{
+		{"", "", ""},
+		{"bucket", "bucket", ""},
+		{"bucket/", "bucket", ""},
+		{"bucket/key", "bucket", "key"},
+		{"bucket/a/b/c", "bucket", "a/b/c"},
+		{"bucket//a/", "bucket", "/a/"},
+	} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+		bucket, key := SplitBucketPath(tt.raw)
+		assert.Equal(t, tt.bucket, bucket, errTag)
+		assert.Equal(t, tt.key, key, errTag)
+	}
+}
+
+func TestJoinBucketPath(t *testing.T) {
+	for i, tt := range []struct {
+		bucket string
+		key    string
+		raw    string
+	}{
+		{"bucket", "", "bucket"},
+		{"bucket", "key", "bucket/key"},
+		{"bucket", "a/b/c", "bucket/a/b/c"},
+	} {
+		errTag := fmt.Sprintf("Test case #%d", i)
+		assert.Equal(t, tt.raw, JoinBucketPath(tt.bucket, tt.key), errTag)
+
+		bucket, key := SplitBucketPath(JoinBucketPath(tt.bucket, tt.key))
+		assert.Equal(t, tt.bucket, bucket, errTag)
+		assert.Equal(t, tt.key, key, errTag)
+	}
+}
@@ -36,7 +36,8 @@ func NewPieceID() PieceID {
 	return id
 }
 
-// PieceIDFromString decodes a hex encoded piece ID string.
+// PieceIDFromString decodes a piece ID from the unpadded base32 form returned
+// by String.
 func PieceIDFromString(s string) (PieceID, error) {
 	idBytes, err := pieceIDEncoding.DecodeString(s)
 	if err != nil {
@@ -5,6 +5,7 @@ package storj_test
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -42,6 +43,24 @@ func TestPieceID_Encode(t *testing.T) {
 	}
 }
 
+func TestPieceIDFromString_Malformed(t *testing.T) {
+	valid := storj.NewPieceID().String()
+
+	for _, s := range []string{
+		"",
+		valid[:len(valid)-1],
+		valid + "A",
+		valid + "====",
+		valid[:len(valid)-8] + "!!!!!!!!",
+		strings.ToLower(valid),
+		valid[:10],
+	} {
+		_, err := storj.PieceIDFromString(s)
+		assert.Error(t, err, s)
+		assert.True(t, storj.ErrPieceID.Has(err), s)
+	}
+}
+
 func TestPieceID_Derive(t *testing.T) {
 	a := storj.NewPieceID()
 	b := storj.NewPieceID()
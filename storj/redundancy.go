@@ -3,6 +3,16 @@
 
 package storj
 
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/zeebo/errs"
+)
+
+// ErrRedundancyAlgorithm is used when a redundancy algorithm is unknown.
+var ErrRedundancyAlgorithm = errs.Class("redundancy algorithm error")
+
 // RedundancyScheme specifies the parameters and the algorithm for redundancy.
 type RedundancyScheme struct {
 	// Algorithm determines the algorithm to be used for redundancy.
@@ -38,6 +48,17 @@ func (scheme RedundancyScheme) StripeSize() int32 {
 	return scheme.ShareSize * int32(scheme.RequiredShares)
 }
 
+// StripeCount returns the number of stripes needed for a segment of segmentSize
+// bytes. The last stripe is padded, so it is counted even when it is partial.
+// It returns 0 when the scheme has no stripe size.
+func (scheme RedundancyScheme) StripeCount(segmentSize int64) int64 {
+	stripeSize := int64(scheme.StripeSize())
+	if stripeSize <= 0 || segmentSize <= 0 {
+		return 0
+	}
+	return (segmentSize + stripeSize - 1) / stripeSize
+}
+
 // DownloadNodes calculates the number of nodes needed to download in the
 // presence of node failure based on t = k + (n-o)k/o.
 func (scheme RedundancyScheme) DownloadNodes() int32 {
@@ -67,3 +88,58 @@ const (
 	InvalidRedundancyAlgorithm = RedundancyAlgorithm(iota)
 	ReedSolomon
 )
+
+// redundancyAlgorithmNames are the names used in the text form of the
+// redundancy algorithms.
+var redundancyAlgorithmNames = map[RedundancyAlgorithm]string{
+	InvalidRedundancyAlgorithm: "invalid",
+	ReedSolomon:                "reed-solomon",
+}
+
+// String returns the name of the redundancy algorithm.
+func (algorithm RedundancyAlgorithm) String() string {
+	if name, ok := redundancyAlgorithmNames[algorithm]; ok {
+		return name
+	}
+	return fmt.Sprintf("RedundancyAlgorithm(%d)", byte(algorithm))
+}
+
+// MarshalText returns the name of the redundancy algorithm, so that JSON
+// renders the algorithm as a name.
+func (algorithm RedundancyAlgorithm) MarshalText() ([]byte, error) {
+	name, ok := redundancyAlgorithmNames[algorithm]
+	if !ok {
+		return nil, ErrRedundancyAlgorithm.New("unknown algorithm %d", byte(algorithm))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText parses the name of a redundancy algorithm.
+func (algorithm *RedundancyAlgorithm) UnmarshalText(text []byte) error {
+	for value, name := range redundancyAlgorithmNames {
+		if name == string(text) {
+			*algorithm = value
+			return nil
+		}
+	}
+	return ErrRedundancyAlgorithm.New("unknown algorithm %q", text)
+}
+
+// UnmarshalJSON parses the name of a redundancy algorithm. The numeric value
+// used before the algorithms were marshaled by name is accepted as well.
+func (algorithm *RedundancyAlgorithm) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		return algorithm.UnmarshalText([]byte(name))
+	}
+
+	var value byte
+	if err := json.Unmarshal(data, &value); err != nil {
+		return ErrRedundancyAlgorithm.New("invalid algorithm %s", data)
+	}
+	if _, ok := redundancyAlgorithmNames[RedundancyAlgorithm(value)]; !ok {
+		return ErrRedundancyAlgorithm.New("unknown algorithm %d", value)
+	}
+	*algorithm = RedundancyAlgorithm(value)
+	return nil
+}
@@ -4,10 +4,12 @@
 package storj_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"storj.io/common/storj"
 )
@@ -38,3 +40,80 @@ func TestRedundancyScheme_DownloadNodes(t *testing.T) {
 		assert.Equal(t, tt.needed, rs.DownloadNodes(), tag)
 	}
 }
+
+func TestRedundancyScheme_Stripes(t *testing.T) {
+	rs := storj.RedundancyScheme{
+		Algorithm:      storj.ReedSolomon,
+		ShareSize:      256,
+		RequiredShares: 29,
+		RepairShares:   35,
+		OptimalShares:  80,
+		TotalShares:    110,
+	}
+
+	assert.Equal(t, int32(256*29), rs.StripeSize())
+	for _, tt := range []struct {
+		segmentSize int64
+		count       int64
+	}{
+		{0, 0},
+		{1, 1},
+		{256 * 29, 1},
+		{256*29 + 1, 2},
+		{64 << 20, 9040},
+	} {
+		assert.Equal(t, tt.count, rs.StripeCount(tt.segmentSize), tt.segmentSize)
+	}
+
+	var zero storj.RedundancyScheme
+	assert.Equal(t, int32(0), zero.StripeSize())
+	assert.Equal(t, int64(0), zero.StripeCount(64<<20))
+}
+
+func TestRedundancyScheme_JSON(t *testing.T) {
+	scheme := storj.RedundancyScheme{
+		Algorithm:      storj.ReedSolomon,
+		ShareSize:      256,
+		RequiredShares: 29,
+		RepairShares:   35,
+		OptimalShares:  80,
+		TotalShares:    110,
+	}
+
+	data, err := json.Marshal(scheme)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"Algorithm": "reed-solomon",
+		"ShareSize": 256,
+		"RequiredShares": 29,
+		"RepairShares": 35,
+		"OptimalShares": 80,
+		"TotalShares": 110
+	}`, string(data))
+
+	var decoded storj.RedundancyScheme
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, scheme, decoded)
+
+	// the numeric form is still accepted
+	decoded = storj.RedundancyScheme{}
+	require.NoError(t, json.Unmarshal([]byte(`{"Algorithm": 1, "ShareSize": 256}`), &decoded))
+	assert.Equal(t, storj.RedundancyScheme{Algorithm: storj.ReedSolomon, ShareSize: 256}, decoded)
+
+	// the zero value round trips as well
+	data, err = json.Marshal(storj.RedundancyScheme{})
+	require.NoError(t, err)
+	decoded = scheme
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, decoded.IsZero())
+
+	_, err = json.Marshal(storj.RedundancyScheme{Algorithm: 5})
+	require.Error(t, err)
+	for _, invalid := range []string{`"reed-salomon"`, `5`, `true`} {
+		err = json.Unmarshal([]byte(`{"Algorithm": `+invalid+`}`), &decoded)
+		require.True(t, storj.ErrRedundancyAlgorithm.Has(err), invalid)
+	}
+
+	assert.Equal(t, "reed-solomon", storj.ReedSolomon.String())
+	assert.Equal(t, "RedundancyAlgorithm(5)", storj.RedundancyAlgorithm(5).String())
+}
@@ -4,6 +4,7 @@
 package storj
 
 import (
+	"crypto/rand"
 	"encoding/base32"
 
 	"github.com/zeebo/errs"
@@ -18,6 +19,33 @@ var segmentIDEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
 // SegmentID is the unique identifier for segment related to object.
 type SegmentID []byte
 
+// SegmentIDSize is the length of segment IDs created with NewSegmentID. It is
+// not required of other segment IDs: the satellite issues signed segment IDs
+// of variable length.
+const SegmentIDSize = 32
+
+// NewSegmentID creates a random segment ID of SegmentIDSize bytes.
+func NewSegmentID() (SegmentID, error) {
+	id := make(SegmentID, SegmentIDSize)
+	if _, err := rand.Read(id); err != nil {
+		return nil, ErrSegmentID.Wrap(err)
+	}
+	return id, nil
+}
+
+// ParseSegmentID decodes a base32 encoded segment ID. Unlike
+// SegmentIDFromString it rejects the empty segment ID.
+func ParseSegmentID(s string) (SegmentID, error) {
+	id, err := SegmentIDFromString(s)
+	if err != nil {
+		return nil, err
+	}
+	if !id.Valid() {
+		return nil, ErrSegmentID.New("empty segment ID")
+	}
+	return id, nil
+}
+
 // SegmentIDFromString decodes an base32 encoded.
 func SegmentIDFromString(s string) (SegmentID, error) {
 	idBytes, err := segmentIDEncoding.DecodeString(s)
@@ -40,6 +68,12 @@ func (id SegmentID) IsZero() bool {
 	return len(id) == 0
 }
 
+// Valid returns whether the segment ID is assigned. Segment IDs have variable
+// length, so the length is not checked.
+func (id SegmentID) Valid() bool {
+	return !id.IsZero()
+}
+
 // String representation of the segment ID.
 func (id SegmentID) String() string { return segmentIDEncoding.EncodeToString(id.Bytes()) }
 
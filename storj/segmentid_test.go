@@ -0,0 +1,56 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storj_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/storj"
+	"storj.io/common/testrand"
+)
+
+func TestNewSegmentID(t *testing.T) {
+	seen := map[string]struct{}{}
+	for i := 0; i < 100; i++ {
+		id, err := storj.NewSegmentID()
+		require.NoError(t, err)
+		require.True(t, id.Valid())
+		require.Len(t, id, storj.SegmentIDSize)
+
+		_, exists := seen[id.String()]
+		require.False(t, exists)
+		seen[id.String()] = struct{}{}
+
+		parsed, err := storj.ParseSegmentID(id.String())
+		require.NoError(t, err)
+		require.Equal(t, id, parsed)
+	}
+}
+
+func TestParseSegmentID(t *testing.T) {
+	// segment IDs issued by the satellite have variable length
+	for _, size := range []int{1, storj.SegmentIDSize - 1, storj.SegmentIDSize + 1, 200} {
+		id := storj.SegmentID(testrand.BytesInt(size))
+		require.True(t, id.Valid())
+
+		parsed, err := storj.ParseSegmentID(id.String())
+		require.NoError(t, err)
+		require.Equal(t, id, parsed)
+	}
+}
+
+func TestParseSegmentID_Invalid(t *testing.T) {
+	require.False(t, storj.SegmentID{}.Valid())
+	require.False(t, storj.SegmentID(nil).Valid())
+
+	_, err := storj.ParseSegmentID("")
+	require.Error(t, err)
+	require.True(t, storj.ErrSegmentID.Has(err))
+
+	_, err = storj.ParseSegmentID("not base32!")
+	require.Error(t, err)
+	require.True(t, storj.ErrSegmentID.Has(err))
+}
@@ -25,10 +25,14 @@ type Cycle struct {
 	stopsent int32
 	runexec  int32
 
-	interval time.Duration
+	interval   time.Duration
+	skipMissed bool
+	onError    func(error)
+	errors     *cycleErrors
 
 	ticker  *time.Ticker
 	control chan interface{}
+	pending chan struct{}
 
 	stopping chan struct{}
 	stopped  chan struct{}
@@ -51,16 +55,137 @@ func NewCycle(interval time.Duration) *Cycle {
 	return cycle
 }
 
+// NewCycleWithSkip creates a new cycle with the specified interval that drops
+// the ticks which happen while `fn` is still running.
+func NewCycleWithSkip(interval time.Duration) *Cycle {
+	cycle := NewCycle(interval)
+	cycle.SetSkipMissed(true)
+	return cycle
+}
+
 // SetInterval allows to change the interval before starting.
 func (cycle *Cycle) SetInterval(interval time.Duration) {
 	cycle.interval = interval
 }
 
+// SetSkipMissed allows to change before starting whether the ticks which
+// happen while `fn` is still running are dropped. Otherwise a tick that
+// happens during a run starts the next run as soon as it finishes.
+func (cycle *Cycle) SetSkipMissed(skip bool) {
+	cycle.skipMissed = skip
+}
+
+// SetOnError allows to set before starting a callback that receives the
+// errors returned by `fn`. When set, an error no longer stops the cycle and
+// it keeps running on the interval. The callback is called in order on a
+// separate goroutine, so a slow callback does not block the cycle. Run waits
+// for the callbacks of all errors before returning.
+func (cycle *Cycle) SetOnError(onError func(error)) {
+	cycle.onError = onError
+}
+
+// runOnce runs `fn` and handles its error and the missed ticks.
+func (cycle *Cycle) runOnce(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		if cycle.onError == nil {
+			return err
+		}
+		cycle.errors.push(err)
+	}
+	cycle.dropMissedTick()
+	return nil
+}
+
+// dropMissedTick discards a tick that happened while `fn` was running, if
+// the cycle is configured to skip them.
+func (cycle *Cycle) dropMissedTick() {
+	if !cycle.skipMissed {
+		return
+	}
+	select {
+	case <-cycle.ticker.C:
+	default:
+	}
+}
+
+// cycleErrors delivers the errors of a Cycle to the callback in order on a
+// single goroutine. The queue is unbounded, so pushing never blocks.
+type cycleErrors struct {
+	onError func(error)
+
+	mu    sync.Mutex
+	queue []error
+
+	signal  chan struct{}
+	closing chan struct{}
+	done    chan struct{}
+}
+
+// newCycleErrors creates a queue that delivers the errors to onError.
+func newCycleErrors(onError func(error)) *cycleErrors {
+	return &cycleErrors{
+		onError: onError,
+		signal:  make(chan struct{}, 1),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// run delivers the queued errors until close is called.
+func (delivery *cycleErrors) run() {
+	defer close(delivery.done)
+	for {
+		select {
+		case <-delivery.signal:
+			delivery.deliver()
+		case <-delivery.closing:
+			delivery.deliver()
+			return
+		}
+	}
+}
+
+// deliver calls the callback for every queued error.
+func (delivery *cycleErrors) deliver() {
+	for {
+		delivery.mu.Lock()
+		if len(delivery.queue) == 0 {
+			delivery.mu.Unlock()
+			return
+		}
+		err := delivery.queue[0]
+		delivery.queue[0] = nil
+		delivery.queue = delivery.queue[1:]
+		delivery.mu.Unlock()
+
+		delivery.onError(err)
+	}
+}
+
+// push queues the error for delivery.
+func (delivery *cycleErrors) push(err error) {
+	delivery.mu.Lock()
+	delivery.queue = append(delivery.queue, err)
+	delivery.mu.Unlock()
+
+	select {
+	case delivery.signal <- struct{}{}:
+	default:
+	}
+}
+
+// close delivers the remaining errors and waits for the delivery to finish.
+func (delivery *cycleErrors) close() {
+	close(delivery.closing)
+	<-delivery.done
+}
+
 func (cycle *Cycle) initialize() {
 	cycle.init.Do(func() {
 		cycle.stopped = make(chan struct{})
 		cycle.stopping = make(chan struct{})
 		cycle.control = make(chan interface{})
+		cycle.pending = make(chan struct{}, 1)
 	})
 }
 
@@ -74,8 +199,10 @@ func (cycle *Cycle) Start(ctx context.Context, group *errgroup.Group, fn func(ct
 
 // Run runs the specified in an interval.
 //
-// Every interval `fn` is started.
-// When `fn` is not fast enough, it may skip some of those executions.
+// Every interval `fn` is started. An error returned by `fn` stops the cycle,
+// unless SetOnError has been used.
+// When `fn` is not fast enough, it may skip some of those executions. Unless
+// SetSkipMissed is enabled, one missed execution starts right after `fn` finishes.
 //
 // Run PANICS if it's called after Stop has been called.
 func (cycle *Cycle) Run(ctx context.Context, fn func(ctx context.Context) error) error {
@@ -83,13 +210,19 @@ func (cycle *Cycle) Run(ctx context.Context, fn func(ctx context.Context) error)
 	cycle.initialize()
 	defer close(cycle.stopped)
 
+	if cycle.onError != nil {
+		cycle.errors = newCycleErrors(cycle.onError)
+		go cycle.errors.run()
+		defer cycle.errors.close()
+	}
+
 	currentInterval := cycle.interval
 	cycle.ticker = time.NewTicker(currentInterval)
 	defer cycle.ticker.Stop()
 
 	choreCtx := monkit.ResetContextSpan(ctx)
 
-	if err := fn(choreCtx); err != nil {
+	if err := cycle.runOnce(choreCtx, fn); err != nil {
 		return err
 	}
 	for {
@@ -132,7 +265,7 @@ func (cycle *Cycle) Run(ctx context.Context, fn func(ctx context.Context) error)
 
 			case cycleTrigger:
 				// trigger the function
-				if err := fn(choreCtx); err != nil {
+				if err := cycle.runOnce(choreCtx, fn); err != nil {
 					return err
 				}
 				if message.done != nil {
@@ -147,9 +280,15 @@ func (cycle *Cycle) Run(ctx context.Context, fn func(ctx context.Context) error)
 			// handle control messages
 			return ctx.Err()
 
+		case <-cycle.pending:
+			// trigger the function
+			if err := cycle.runOnce(choreCtx, fn); err != nil {
+				return err
+			}
+
 		case <-cycle.ticker.C:
 			// trigger the function
-			if err := fn(choreCtx); err != nil {
+			if err := cycle.runOnce(choreCtx, fn); err != nil {
 				return err
 			}
 		}
@@ -211,6 +350,17 @@ func (cycle *Cycle) Trigger() {
 	cycle.sendControl(cycleTrigger{})
 }
 
+// TriggerAsync schedules the loop to be done as soon as possible without waiting
+// for the interval to elapse. Unlike Trigger it doesn't block; multiple calls
+// while the loop is running are coalesced into a single pending run.
+func (cycle *Cycle) TriggerAsync() {
+	cycle.initialize()
+	select {
+	case cycle.pending <- struct{}{}:
+	default:
+	}
+}
+
 // TriggerWait ensures that the loop is done at least once and waits for completion.
 // If it's currently running it waits for the previous to complete and then runs.
 func (cycle *Cycle) TriggerWait() {
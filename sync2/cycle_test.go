@@ -5,6 +5,7 @@ package sync2_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync/atomic"
 	"testing"
@@ -88,6 +89,61 @@ func TestCycle_Basic(t *testing.T) {
 	}
 }
 
+func TestCycle_TriggerAsync(t *testing.T) {
+	t.Parallel()
+
+	cycle := sync2.NewCycle(time.Hour)
+	defer cycle.Close()
+
+	ctx := context.Background()
+
+	var count int64
+	runs := make(chan int64, 10)
+	release := make(chan struct{})
+
+	var group errgroup.Group
+	cycle.Start(ctx, &group, func(ctx context.Context) error {
+		run := atomic.AddInt64(&count, 1)
+		runs <- run
+		if run == 2 {
+			<-release
+		}
+		return nil
+	})
+
+	waitRun := func(expected int64) {
+		select {
+		case run := <-runs:
+			require.Equal(t, expected, run)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("run %d did not happen", expected)
+		}
+	}
+
+	// initial run
+	waitRun(1)
+
+	// triggering runs the function well before the interval elapses
+	cycle.TriggerAsync()
+	waitRun(2)
+
+	// triggers while running are coalesced into a single pending run
+	for i := 0; i < 5; i++ {
+		cycle.TriggerAsync()
+	}
+	close(release)
+	waitRun(3)
+
+	select {
+	case run := <-runs:
+		t.Fatalf("unexpected run %d", run)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cycle.Stop()
+	require.NoError(t, group.Wait())
+}
+
 func TestCycle_MultipleStops(t *testing.T) {
 	t.Parallel()
 
@@ -181,3 +237,153 @@ func TestCycle_Stop_EnsureLoopIsFinished(t *testing.T) {
 
 	require.Equal(t, atomic.LoadInt64(&completed), int64(1))
 }
+
+func TestCycle_SkipMissed(t *testing.T) {
+	t.Parallel()
+
+	const (
+		interval = 50 * time.Millisecond
+		duration = 2*interval + 20*time.Millisecond
+	)
+
+	for _, skip := range []bool{false, true} {
+		skip := skip
+		t.Run(fmt.Sprint(skip), func(t *testing.T) {
+			t.Parallel()
+
+			cycle := sync2.NewCycle(interval)
+			if skip {
+				cycle = sync2.NewCycleWithSkip(interval)
+			}
+			defer cycle.Close()
+
+			type run struct{ start, end time.Time }
+			var runs []run
+			var running int32
+
+			ctx := context.Background()
+			done := make(chan struct{})
+
+			var group errgroup.Group
+			cycle.Start(ctx, &group, func(ctx context.Context) error {
+				if !atomic.CompareAndSwapInt32(&running, 0, 1) {
+					return errors.New("overlapping run")
+				}
+				defer atomic.StoreInt32(&running, 0)
+
+				start := time.Now()
+				time.Sleep(duration)
+				if len(runs) < 4 {
+					runs = append(runs, run{start, time.Now()})
+					if len(runs) == 4 {
+						close(done)
+					}
+				}
+				return nil
+			})
+			group.Go(func() error {
+				<-done
+				cycle.Stop()
+				return nil
+			})
+			require.NoError(t, group.Wait())
+
+			require.Len(t, runs, 4)
+			for i := 1; i < len(runs); i++ {
+				gap := runs[i].start.Sub(runs[i-1].end)
+				require.True(t, gap >= 0, "runs overlap")
+				if skip {
+					// the tick during the run is dropped and the next one is waited for
+					require.True(t, gap >= 5*time.Millisecond, "run %d started %v after the previous", i, gap)
+				}
+			}
+		})
+	}
+}
+
+func TestCycle_OnError(t *testing.T) {
+	t.Parallel()
+
+	cycle := sync2.NewCycle(time.Millisecond)
+	defer cycle.Close()
+
+	failure := errors.New("run failed")
+	received := make(chan error, 10)
+	var reported int32
+	cycle.SetOnError(func(err error) {
+		atomic.AddInt32(&reported, 1)
+		select {
+		case received <- err:
+		default:
+		}
+	})
+
+	var runs int32
+	ctx := context.Background()
+
+	var group errgroup.Group
+	cycle.Start(ctx, &group, func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return failure
+	})
+
+	for i := 0; i < 3; i++ {
+		select {
+		case err := <-received:
+			require.Equal(t, failure, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("error was not received")
+		}
+	}
+
+	cycle.Stop()
+	require.NoError(t, group.Wait())
+	require.True(t, atomic.LoadInt32(&runs) >= 3)
+
+	// every failed run has been reported by the time the cycle stops
+	require.Equal(t, atomic.LoadInt32(&runs), atomic.LoadInt32(&reported))
+}
+
+func TestCycle_OnErrorDoesNotBlock(t *testing.T) {
+	t.Parallel()
+
+	cycle := sync2.NewCycle(time.Millisecond)
+	defer cycle.Close()
+
+	release := make(chan struct{})
+	var reported []string
+	cycle.SetOnError(func(err error) {
+		<-release
+		reported = append(reported, err.Error())
+	})
+
+	var runs int32
+	ranWhileBlocked := make(chan struct{})
+	ctx := context.Background()
+
+	var group errgroup.Group
+	cycle.Start(ctx, &group, func(ctx context.Context) error {
+		run := atomic.AddInt32(&runs, 1)
+		if run == 5 {
+			close(ranWhileBlocked)
+		}
+		return fmt.Errorf("run %d failed", run)
+	})
+
+	// the cycle keeps running while the first callback is blocked
+	select {
+	case <-ranWhileBlocked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("blocked callback delayed the cycle")
+	}
+
+	close(release)
+	cycle.Stop()
+	require.NoError(t, group.Wait())
+
+	// errors are delivered in order, all of them before Run returns
+	require.Len(t, reported, int(atomic.LoadInt32(&runs)))
+	for i, message := range reported {
+		require.Equal(t, fmt.Sprintf("run %d failed", i+1), message)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2
+
+import (
+	"sync"
+	"time"
+)
+
+// Debouncer coalesces bursts of triggers into a single call, which happens
+// once no new trigger has arrived for the wait duration.
+type Debouncer struct {
+	noCopy noCopy // nolint: structcheck
+
+	wait time.Duration
+	fn   func()
+
+	mu      sync.Mutex
+	timer   *time.Timer
+	stopped bool
+}
+
+// NewDebouncer creates a Debouncer that calls fn after wait has elapsed
+// without new triggers.
+func NewDebouncer(wait time.Duration, fn func()) *Debouncer {
+	return &Debouncer{wait: wait, fn: fn}
+}
+
+// Trigger (re)starts the timer. fn is called once wait elapses without
+// another Trigger. It has no effect after Stop has been called.
+func (debouncer *Debouncer) Trigger() {
+	debouncer.mu.Lock()
+	defer debouncer.mu.Unlock()
+
+	if debouncer.stopped {
+		return
+	}
+	if debouncer.timer == nil {
+		debouncer.timer = time.AfterFunc(debouncer.wait, debouncer.fn)
+		return
+	}
+	debouncer.timer.Stop()
+	debouncer.timer.Reset(debouncer.wait)
+}
+
+// Stop cancels a pending call and ignores any further triggers. It does not
+// wait for a call of fn that has already started.
+func (debouncer *Debouncer) Stop() {
+	debouncer.mu.Lock()
+	defer debouncer.mu.Unlock()
+
+	debouncer.stopped = true
+	if debouncer.timer != nil {
+		debouncer.timer.Stop()
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/sync2"
+)
+
+func TestDebouncer(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	debouncer := sync2.NewDebouncer(100*time.Millisecond, func() {
+		atomic.AddInt32(&calls, 1)
+	})
+	defer debouncer.Stop()
+
+	for i := 0; i < 5; i++ {
+		debouncer.Trigger()
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Equal(t, int32(0), atomic.LoadInt32(&calls))
+
+	time.Sleep(300 * time.Millisecond)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// a new burst results in another call
+	debouncer.Trigger()
+	debouncer.Trigger()
+	time.Sleep(300 * time.Millisecond)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestDebouncer_Stop(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	debouncer := sync2.NewDebouncer(50*time.Millisecond, func() {
+		atomic.AddInt32(&calls, 1)
+	})
+
+	debouncer.Trigger()
+	debouncer.Stop()
+	debouncer.Trigger()
+
+	time.Sleep(200 * time.Millisecond)
+	require.Equal(t, int32(0), atomic.LoadInt32(&calls))
+}
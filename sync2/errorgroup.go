@@ -0,0 +1,57 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2
+
+import (
+	"context"
+	"sync"
+)
+
+// ErrorWaitGroup is a group of workers that returns the first error on Wait.
+// The first error cancels the context shared by the workers.
+//
+// The zero value is usable, but then there is no context to cancel.
+type ErrorWaitGroup struct {
+	noCopy noCopy // nolint: structcheck
+
+	cancel context.CancelFunc
+
+	wg   sync.WaitGroup
+	once sync.Once
+	err  error
+}
+
+// NewErrorWaitGroup returns a new ErrorWaitGroup and a context derived from
+// ctx. The context is canceled when a worker fails or Wait returns.
+func NewErrorWaitGroup(ctx context.Context) (*ErrorWaitGroup, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &ErrorWaitGroup{cancel: cancel}, ctx
+}
+
+// Go starts fn in a new goroutine. The first non-nil error returned by any
+// fn cancels the shared context and is returned by Wait.
+func (group *ErrorWaitGroup) Go(fn func() error) {
+	group.wg.Add(1)
+	go func() {
+		defer group.wg.Done()
+
+		if err := fn(); err != nil {
+			group.once.Do(func() {
+				group.err = err
+				if group.cancel != nil {
+					group.cancel()
+				}
+			})
+		}
+	}()
+}
+
+// Wait waits for all workers to finish and returns the first non-nil error.
+func (group *ErrorWaitGroup) Wait() error {
+	group.wg.Wait()
+	if group.cancel != nil {
+		group.cancel()
+	}
+	return group.err
+}
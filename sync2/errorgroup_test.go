@@ -0,0 +1,62 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/sync2"
+)
+
+func TestErrorWaitGroup(t *testing.T) {
+	t.Parallel()
+
+	group, ctx := sync2.NewErrorWaitGroup(context.Background())
+
+	first := errors.New("first")
+	failed := make(chan struct{})
+	group.Go(func() error {
+		defer close(failed)
+		return first
+	})
+
+	canceled := make(chan error, 1)
+	group.Go(func() error {
+		<-failed
+		select {
+		case <-ctx.Done():
+			canceled <- ctx.Err()
+		case <-time.After(5 * time.Second):
+			canceled <- errors.New("context was not canceled")
+		}
+		return errors.New("second")
+	})
+
+	group.Go(func() error { return nil })
+
+	require.Equal(t, first, group.Wait())
+	require.Equal(t, context.Canceled, <-canceled)
+}
+
+func TestErrorWaitGroup_NoError(t *testing.T) {
+	t.Parallel()
+
+	group, ctx := sync2.NewErrorWaitGroup(context.Background())
+	for i := 0; i < 10; i++ {
+		group.Go(func() error {
+			return ctx.Err()
+		})
+	}
+	require.NoError(t, group.Wait())
+	require.Error(t, ctx.Err())
+
+	var zero sync2.ErrorWaitGroup
+	zero.Go(func() error { return nil })
+	require.NoError(t, zero.Wait())
+}
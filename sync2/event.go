@@ -0,0 +1,68 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2
+
+import (
+	"context"
+	"sync"
+)
+
+// Event allows to wait for a signal. Unlike Fence it can be reset.
+type Event struct {
+	noCopy noCopy // nolint: structcheck
+
+	mu       sync.Mutex
+	signaled bool
+	done     chan struct{}
+}
+
+// init sets up the channel, must be called with mu held.
+func (event *Event) init() {
+	if event.done == nil {
+		event.done = make(chan struct{})
+	}
+}
+
+// Signal releases everyone waiting in Wait until Reset is called.
+func (event *Event) Signal() {
+	event.mu.Lock()
+	defer event.mu.Unlock()
+
+	event.init()
+	if !event.signaled {
+		event.signaled = true
+		close(event.done)
+	}
+}
+
+// Reset re-arms the event, so that Wait blocks until the next Signal.
+func (event *Event) Reset() {
+	event.mu.Lock()
+	defer event.mu.Unlock()
+
+	if event.signaled {
+		event.signaled = false
+		event.done = make(chan struct{})
+	}
+}
+
+// Wait waits until Signal has been called since the last Reset.
+func (event *Event) Wait(ctx context.Context) error {
+	event.mu.Lock()
+	event.init()
+	done := event.done
+	event.mu.Unlock()
+
+	select {
+	case <-done:
+		return nil
+	default:
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-done:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/common/sync2"
+	"storj.io/common/testcontext"
+)
+
+func TestEvent(t *testing.T) {
+	t.Parallel()
+
+	ctx := testcontext.NewWithTimeout(t, 30*time.Second)
+	defer ctx.Cleanup()
+
+	var event sync2.Event
+
+	waitAll := func() *errgroup.Group {
+		var group errgroup.Group
+		for i := 0; i < 10; i++ {
+			group.Go(func() error {
+				return event.Wait(ctx)
+			})
+		}
+		return &group
+	}
+
+	group := waitAll()
+	// wait a bit for all goroutines to start waiting
+	time.Sleep(100 * time.Millisecond)
+	event.Signal()
+	require.NoError(t, group.Wait())
+
+	// once signaled, waiting returns immediately
+	require.NoError(t, event.Wait(ctx))
+	event.Signal()
+
+	event.Reset()
+
+	shortctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	require.Equal(t, context.DeadlineExceeded, event.Wait(shortctx))
+
+	group = waitAll()
+	time.Sleep(100 * time.Millisecond)
+	event.Signal()
+	require.NoError(t, group.Wait())
+}
@@ -0,0 +1,23 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2
+
+// Waiters returns the number of callers waiting for the attempt in progress.
+func (once *RunOnce) Waiters() int {
+	once.mu.Lock()
+	defer once.mu.Unlock()
+	if once.inflight == nil {
+		return 0
+	}
+	return once.inflight.waiters
+}
+
+// TryAcquire acquires n units of the semaphore without waiting and reports
+// whether it succeeded. It fails when there are waiters.
+func (sema *Semaphore) TryAcquire(n int64) bool {
+	if n <= 0 || sema.ctx.Err() != nil {
+		return false
+	}
+	return sema.sema.TryAcquire(n)
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2
+
+import (
+	"container/list"
+	"sync"
+)
+
+// LRU is a concurrency safe cache which evicts the least recently used
+// entries when it grows beyond its capacity.
+//
+// Keys must be comparable, as they're used as map keys.
+type LRU struct {
+	noCopy noCopy // nolint: structcheck
+
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[interface{}]*list.Element
+}
+
+// lruEntry is the value stored in the LRU order list.
+type lruEntry struct {
+	key   interface{}
+	value interface{}
+}
+
+// NewLRU creates a new LRU holding at most capacity entries.
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[interface{}]*list.Element),
+	}
+}
+
+// Get returns the value for key and marks it as recently used.
+func (lru *LRU) Get(key interface{}) (value interface{}, ok bool) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	elem, ok := lru.entries[key]
+	if !ok {
+		return nil, false
+	}
+	lru.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).value, true
+}
+
+// Put adds or replaces the value for key, evicting the least recently used
+// entries when the capacity is exceeded.
+func (lru *LRU) Put(key, value interface{}) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	if elem, ok := lru.entries[key]; ok {
+		elem.Value.(*lruEntry).value = value
+		lru.order.MoveToFront(elem)
+		return
+	}
+
+	lru.entries[key] = lru.order.PushFront(&lruEntry{key: key, value: value})
+	for lru.order.Len() > lru.capacity {
+		oldest := lru.order.Back()
+		lru.order.Remove(oldest)
+		delete(lru.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Remove removes the entry for key, if it exists.
+func (lru *LRU) Remove(key interface{}) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	if elem, ok := lru.entries[key]; ok {
+		lru.order.Remove(elem)
+		delete(lru.entries, key)
+	}
+}
+
+// Len returns the number of entries in the LRU.
+func (lru *LRU) Len() int {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	return lru.order.Len()
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/common/sync2"
+)
+
+func TestLRU_Eviction(t *testing.T) {
+	lru := sync2.NewLRU(2)
+
+	lru.Put("a", 1)
+	lru.Put("b", 2)
+
+	// mark "a" as recently used, so "b" gets evicted
+	value, ok := lru.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	lru.Put("c", 3)
+	assert.Equal(t, 2, lru.Len())
+
+	_, ok = lru.Get("b")
+	assert.False(t, ok)
+
+	value, ok = lru.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	value, ok = lru.Get("c")
+	require.True(t, ok)
+	assert.Equal(t, 3, value)
+
+	// replacing a value marks it as recently used
+	lru.Put("a", 10)
+	lru.Put("d", 4)
+	_, ok = lru.Get("c")
+	assert.False(t, ok)
+	value, ok = lru.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, 10, value)
+
+	lru.Remove("a")
+	_, ok = lru.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 1, lru.Len())
+
+	lru.Remove("missing")
+	assert.Equal(t, 1, lru.Len())
+}
+
+func TestLRU_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	const capacity = 10
+	lru := sync2.NewLRU(capacity)
+
+	var group errgroup.Group
+	for i := 0; i < 10; i++ {
+		i := i
+		group.Go(func() error {
+			for k := 0; k < 1000; k++ {
+				key := (i*1000 + k) % 37
+				lru.Put(key, k)
+				lru.Get(key)
+				if k%3 == 0 {
+					lru.Remove(key)
+				}
+			}
+			return nil
+		})
+	}
+	require.NoError(t, group.Wait())
+	assert.True(t, lru.Len() <= capacity)
+}
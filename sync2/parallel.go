@@ -0,0 +1,47 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Parallel calls fn for every index in [0, n) using at most workers
+// goroutines. Callers collect results in order by storing the result for
+// index i in a preallocated slice at position i.
+//
+// The first error cancels the context passed to the remaining calls, stops
+// handing out further indexes and is returned. If workers is not positive,
+// a single worker is used.
+func Parallel(ctx context.Context, n, workers int, fn func(ctx context.Context, i int) error) error {
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+
+	group, ctx := NewErrorWaitGroup(ctx)
+
+	var next int64 = -1
+	for worker := 0; worker < workers; worker++ {
+		group.Go(func() error {
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= n {
+					return nil
+				}
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				if err := fn(ctx, i); err != nil {
+					return err
+				}
+			}
+		})
+	}
+
+	return group.Wait()
+}
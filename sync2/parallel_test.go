@@ -0,0 +1,75 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/sync2"
+)
+
+func TestParallel(t *testing.T) {
+	ctx := context.Background()
+
+	in := make([]int, 100)
+	for i := range in {
+		in[i] = i
+	}
+	out := make([]int, len(in))
+
+	var running, maxRunning int64
+	err := sync2.Parallel(ctx, len(in), 4, func(ctx context.Context, i int) error {
+		current := atomic.AddInt64(&running, 1)
+		defer atomic.AddInt64(&running, -1)
+		for {
+			max := atomic.LoadInt64(&maxRunning)
+			if current <= max || atomic.CompareAndSwapInt64(&maxRunning, max, current) {
+				break
+			}
+		}
+
+		out[i] = in[i] * in[i]
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, maxRunning <= 4, maxRunning)
+
+	for i := range in {
+		assert.Equal(t, i*i, out[i])
+	}
+}
+
+func TestParallel_Error(t *testing.T) {
+	ctx := context.Background()
+
+	failure := errors.New("failure")
+
+	var calls int64
+	err := sync2.Parallel(ctx, 100, 4, func(ctx context.Context, i int) error {
+		atomic.AddInt64(&calls, 1)
+		if i == 10 {
+			return failure
+		}
+		if i > 10 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+	require.True(t, errors.Is(err, failure))
+	assert.True(t, atomic.LoadInt64(&calls) < 100, calls)
+}
+
+func TestParallel_Empty(t *testing.T) {
+	err := sync2.Parallel(context.Background(), 0, 4, func(ctx context.Context, i int) error {
+		return errors.New("unexpected call")
+	})
+	require.NoError(t, err)
+}
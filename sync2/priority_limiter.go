@@ -0,0 +1,116 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// PriorityLimiter implements concurrent goroutine limiting where queued
+// functions with a higher priority are started before lower priority ones.
+//
+// To avoid starving low priority functions, every function that is started
+// while a function is queued raises the priority of the queued function by one.
+type PriorityLimiter struct {
+	noCopy noCopy // nolint: structcheck
+
+	mu      sync.Mutex
+	limit   int
+	running int
+	started int64
+	queued  int64
+	queue   priorityQueue
+	working sync.WaitGroup
+}
+
+// NewPriorityLimiter creates a new priority limiter with limit set to n.
+func NewPriorityLimiter(n int) *PriorityLimiter {
+	return &PriorityLimiter{limit: n}
+}
+
+// Go starts fn as a goroutine when the limit is not reached and otherwise
+// queues it to be started when a running goroutine finishes.
+func (limiter *PriorityLimiter) Go(priority int, fn func()) {
+	limiter.working.Add(1)
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	if limiter.running < limiter.limit {
+		limiter.start(fn)
+		return
+	}
+
+	heap.Push(&limiter.queue, &priorityTask{
+		// a task gains one priority for every task started after it was queued,
+		// which keeps the ordering the same as when comparing aged priorities.
+		priority: int64(priority) - limiter.started,
+		sequence: limiter.queued,
+		fn:       fn,
+	})
+	limiter.queued++
+}
+
+// start runs fn in a goroutine, must be called with mu held.
+func (limiter *PriorityLimiter) start(fn func()) {
+	limiter.running++
+	limiter.started++
+
+	go func() {
+		defer limiter.working.Done()
+		defer limiter.finish()
+
+		fn()
+	}()
+}
+
+// finish starts the next queued task in place of a finished one.
+func (limiter *PriorityLimiter) finish() {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	limiter.running--
+	if limiter.queue.Len() > 0 {
+		task := heap.Pop(&limiter.queue).(*priorityTask)
+		limiter.start(task.fn)
+	}
+}
+
+// Wait waits for all running and queued goroutines to finish.
+func (limiter *PriorityLimiter) Wait() {
+	limiter.working.Wait()
+}
+
+// priorityTask is a function queued in a PriorityLimiter.
+type priorityTask struct {
+	priority int64
+	sequence int64
+	fn       func()
+}
+
+// priorityQueue implements heap.Interface with the highest priority first.
+// Tasks with the same priority are ordered by when they were queued.
+type priorityQueue []*priorityTask
+
+func (queue priorityQueue) Len() int { return len(queue) }
+
+func (queue priorityQueue) Less(i, k int) bool {
+	if queue[i].priority != queue[k].priority {
+		return queue[i].priority > queue[k].priority
+	}
+	return queue[i].sequence < queue[k].sequence
+}
+
+func (queue priorityQueue) Swap(i, k int) { queue[i], queue[k] = queue[k], queue[i] }
+
+func (queue *priorityQueue) Push(x interface{}) { *queue = append(*queue, x.(*priorityTask)) }
+
+func (queue *priorityQueue) Pop() interface{} {
+	old := *queue
+	task := old[len(old)-1]
+	old[len(old)-1] = nil
+	*queue = old[:len(old)-1]
+	return task
+}
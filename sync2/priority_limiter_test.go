@@ -0,0 +1,100 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/sync2"
+)
+
+func TestPriorityLimiterLimiting(t *testing.T) {
+	t.Parallel()
+
+	const N, Limit = 1000, 10
+	limiter := sync2.NewPriorityLimiter(Limit)
+	counter := int32(0)
+	for i := 0; i < N; i++ {
+		limiter.Go(i%3, func() {
+			if atomic.AddInt32(&counter, 1) > Limit {
+				panic("limit exceeded")
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&counter, -1)
+		})
+	}
+	limiter.Wait()
+}
+
+func TestPriorityLimiterOrdering(t *testing.T) {
+	t.Parallel()
+
+	limiter := sync2.NewPriorityLimiter(1)
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func() {
+		return func() {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}
+	}
+
+	block := make(chan struct{})
+	limiter.Go(0, func() { <-block })
+
+	limiter.Go(0, record("low1"))
+	limiter.Go(0, record("low2"))
+	limiter.Go(10, record("high"))
+	limiter.Go(1, record("medium"))
+
+	close(block)
+	limiter.Wait()
+
+	require.Equal(t, []string{"high", "medium", "low1", "low2"}, order)
+}
+
+func TestPriorityLimiterAging(t *testing.T) {
+	t.Parallel()
+
+	limiter := sync2.NewPriorityLimiter(1)
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) {
+		mu.Lock()
+		order = append(order, name)
+		mu.Unlock()
+	}
+
+	// keep queueing high priority tasks from the running task, so that the
+	// low priority task would never run without aging.
+	var queueHigh func(n int)
+	queueHigh = func(n int) {
+		if n == 0 {
+			return
+		}
+		limiter.Go(2, func() {
+			record("high")
+			queueHigh(n - 1)
+		})
+	}
+
+	block := make(chan struct{})
+	limiter.Go(0, func() { <-block })
+	limiter.Go(0, func() { record("low") })
+	queueHigh(10)
+
+	close(block)
+	limiter.Wait()
+
+	require.Contains(t, order, "low")
+	require.NotEqual(t, "low", order[len(order)-1])
+}
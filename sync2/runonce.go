@@ -0,0 +1,77 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2
+
+import (
+	"context"
+	"sync"
+
+	"github.com/zeebo/errs"
+)
+
+// RunOnce runs a fallible initialization until it succeeds.
+//
+// Unlike sync.Once a failed attempt is not remembered, so the next Do retries.
+// Concurrent calls share a single attempt.
+type RunOnce struct {
+	noCopy noCopy // nolint: structcheck
+
+	mu       sync.Mutex
+	done     bool
+	inflight *runOnceAttempt
+}
+
+// runOnceAttempt is an attempt in progress.
+type runOnceAttempt struct {
+	finished chan struct{}
+	waiters  int // callers waiting for the attempt, protected by RunOnce.mu
+	err      error
+}
+
+// Do calls fn unless a previous call has succeeded. When an attempt is already
+// in progress, Do waits for it and returns its result instead of calling fn.
+// The attempt uses the context of the caller that started it. If fn panics,
+// the waiting callers get an error and the next Do retries.
+func (once *RunOnce) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	once.mu.Lock()
+	if once.done {
+		once.mu.Unlock()
+		return nil
+	}
+	if attempt := once.inflight; attempt != nil {
+		attempt.waiters++
+		once.mu.Unlock()
+		select {
+		case <-attempt.finished:
+			return attempt.err
+		case <-ctx.Done():
+			once.mu.Lock()
+			attempt.waiters--
+			once.mu.Unlock()
+			return ctx.Err()
+		}
+	}
+
+	attempt := &runOnceAttempt{finished: make(chan struct{})}
+	once.inflight = attempt
+	once.mu.Unlock()
+
+	returned := false
+	defer func() {
+		if !returned {
+			attempt.err = errs.New("run once attempt panicked")
+		}
+
+		once.mu.Lock()
+		once.done = attempt.err == nil
+		once.inflight = nil
+		close(attempt.finished)
+		once.mu.Unlock()
+	}()
+
+	attempt.err = fn(ctx)
+	returned = true
+
+	return attempt.err
+}
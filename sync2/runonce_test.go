@@ -0,0 +1,172 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/sync2"
+	"storj.io/common/testcontext"
+)
+
+func TestRunOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx := testcontext.NewWithTimeout(t, 30*time.Second)
+	defer ctx.Cleanup()
+
+	var once sync2.RunOnce
+	var calls int32
+	failure := errors.New("failure")
+
+	err := once.Do(ctx, func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return failure
+	})
+	require.Equal(t, failure, err)
+
+	err = once.Do(ctx, func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = once.Do(ctx, func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return failure
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+// waitWaiters waits until n callers are waiting for the attempt of once.
+func waitWaiters(ctx context.Context, once *sync2.RunOnce, n int) error {
+	for once.Waiters() < n {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		runtime.Gosched()
+	}
+	return nil
+}
+
+func TestRunOnce_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	ctx := testcontext.NewWithTimeout(t, 30*time.Second)
+	defer ctx.Cleanup()
+
+	var once sync2.RunOnce
+	var calls int32
+	failure := errors.New("failure")
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	attempt := func(ctx context.Context) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+		}
+		<-release
+		return failure
+	}
+
+	results := make(chan error, 10)
+	go func() { results <- once.Do(ctx, attempt) }()
+	<-started
+
+	for i := 0; i < 9; i++ {
+		go func() { results <- once.Do(ctx, attempt) }()
+	}
+	require.NoError(t, waitWaiters(ctx, &once, 9))
+	close(release)
+
+	// every caller got the result of the single attempt
+	for i := 0; i < 10; i++ {
+		require.Equal(t, failure, <-results)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestRunOnce_WaiterCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx := testcontext.NewWithTimeout(t, 30*time.Second)
+	defer ctx.Cleanup()
+
+	var once sync2.RunOnce
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	result := make(chan error, 1)
+	go func() {
+		result <- once.Do(ctx, func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	waitCtx, cancel := context.WithCancel(ctx)
+	waited := make(chan error, 1)
+	go func() {
+		waited <- once.Do(waitCtx, func(ctx context.Context) error { return nil })
+	}()
+	require.NoError(t, waitWaiters(ctx, &once, 1))
+
+	// a canceled waiter stops waiting and no longer counts as waiting
+	cancel()
+	require.Equal(t, context.Canceled, <-waited)
+	require.Equal(t, 0, once.Waiters())
+
+	close(release)
+	require.NoError(t, <-result)
+}
+
+func TestRunOnce_Panic(t *testing.T) {
+	t.Parallel()
+
+	ctx := testcontext.NewWithTimeout(t, 30*time.Second)
+	defer ctx.Cleanup()
+
+	var once sync2.RunOnce
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	panicked := make(chan interface{}, 1)
+	go func() {
+		defer func() { panicked <- recover() }()
+		_ = once.Do(ctx, func(ctx context.Context) error {
+			close(started)
+			<-release
+			panic("failure")
+		})
+	}()
+	<-started
+
+	waited := make(chan error, 1)
+	go func() {
+		waited <- once.Do(ctx, func(ctx context.Context) error { return nil })
+	}()
+	require.NoError(t, waitWaiters(ctx, &once, 1))
+	close(release)
+
+	require.Equal(t, "failure", <-panicked)
+	require.Error(t, <-waited)
+
+	// the next call retries
+	var calls int32
+	require.NoError(t, once.Do(ctx, func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}))
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
@@ -6,15 +6,20 @@ package sync2
 import (
 	"context"
 
+	"github.com/zeebo/errs"
 	"golang.org/x/sync/semaphore"
 )
 
 // Semaphore implements a closable semaphore.
+//
+// Waiters are served in FIFO order, so a large Acquire is not starved by
+// smaller ones queued after it.
 type Semaphore struct {
 	noCopy noCopy // nolint: structcheck
 
 	ctx   context.Context
 	close func()
+	size  int64
 	sema  *semaphore.Weighted
 }
 
@@ -28,7 +33,8 @@ func NewSemaphore(size int) *Semaphore {
 // Init initializes semaphore to the specified size.
 func (sema *Semaphore) Init(size int) {
 	sema.ctx, sema.close = context.WithCancel(context.Background())
-	sema.sema = semaphore.NewWeighted(int64(size))
+	sema.size = int64(size)
+	sema.sema = semaphore.NewWeighted(sema.size)
 }
 
 // Close closes the semaphore from further use.
@@ -45,3 +51,47 @@ func (sema *Semaphore) Lock() bool {
 func (sema *Semaphore) Unlock() {
 	sema.sema.Release(1)
 }
+
+// Acquire acquires n units of the semaphore, waiting until they are available,
+// ctx is canceled or the semaphore is closed. Acquiring a non-positive number
+// of units or more units than the semaphore size fails immediately.
+//
+// Acquiring without waiting is cheap. A waiting Acquire with a cancelable ctx
+// starts a goroutine that wakes it when the semaphore is closed.
+func (sema *Semaphore) Acquire(ctx context.Context, n int64) error {
+	if n <= 0 {
+		return errs.New("acquiring %d units of semaphore", n)
+	}
+	if n > sema.size {
+		return errs.New("acquiring %d exceeds semaphore size %d", n, sema.size)
+	}
+	if err := sema.ctx.Err(); err != nil {
+		return err
+	}
+	if sema.sema.TryAcquire(n) {
+		return nil
+	}
+
+	// a context that is never canceled only needs to wait for Close.
+	if ctx.Done() == nil {
+		return sema.sema.Acquire(sema.ctx, n)
+	}
+
+	// wake up the waiter when the semaphore is closed as well.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-sema.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return sema.sema.Acquire(ctx, n)
+}
+
+// Release releases n units of the semaphore.
+func (sema *Semaphore) Release(n int64) {
+	sema.sema.Release(n)
+}
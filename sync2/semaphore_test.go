@@ -0,0 +1,112 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package sync2_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/sync2"
+)
+
+// waitQueued waits until another Acquire is queued on sema. One unit of
+// sema must be available, which TryAcquire only takes when nobody is waiting.
+func waitQueued(sema *sync2.Semaphore) {
+	for sema.TryAcquire(1) {
+		sema.Release(1)
+		runtime.Gosched()
+	}
+}
+
+func TestSemaphore_FIFO(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	sema := sync2.NewSemaphore(10)
+	defer sema.Close()
+
+	// keep a single unit available
+	require.NoError(t, sema.Acquire(ctx, 9))
+
+	order := make(chan string, 2)
+	acquire := func(name string, n int64) {
+		go func() {
+			if err := sema.Acquire(ctx, n); err != nil {
+				order <- err.Error()
+				return
+			}
+			order <- name
+			sema.Release(n)
+		}()
+	}
+
+	acquire("large", 10)
+	waitQueued(sema)
+
+	// the available unit must not let "small" jump ahead of "large"
+	acquire("small", 1)
+
+	sema.Release(9)
+	require.Equal(t, "large", <-order)
+	require.Equal(t, "small", <-order)
+}
+
+func TestSemaphore_CloseWakesAcquire(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	sema := sync2.NewSemaphore(2)
+	require.NoError(t, sema.Acquire(ctx, 1))
+
+	// one waiter with a cancelable context, one with a context that is never canceled
+	acquired := make(chan error, 2)
+	go func() { acquired <- sema.Acquire(ctx, 2) }()
+	waitQueued(sema)
+	go func() { acquired <- sema.Acquire(context.Background(), 2) }()
+
+	sema.Close()
+	require.Error(t, <-acquired)
+	require.Error(t, <-acquired)
+	require.NoError(t, ctx.Err())
+
+	require.Error(t, sema.Acquire(ctx, 1))
+	require.False(t, sema.TryAcquire(1))
+}
+
+func TestSemaphore_AcquireInvalid(t *testing.T) {
+	t.Parallel()
+
+	sema := sync2.NewSemaphore(5)
+	defer sema.Close()
+
+	ctx := context.Background()
+	require.Error(t, sema.Acquire(ctx, 0))
+	require.Error(t, sema.Acquire(ctx, -1))
+	require.False(t, sema.TryAcquire(-1))
+
+	require.NoError(t, sema.Acquire(ctx, 5))
+	sema.Release(5)
+}
+
+func TestSemaphore_AcquireTooLarge(t *testing.T) {
+	t.Parallel()
+
+	sema := sync2.NewSemaphore(5)
+	defer sema.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	require.Error(t, sema.Acquire(ctx, 6))
+	require.NoError(t, ctx.Err())
+
+	require.NoError(t, sema.Acquire(ctx, 5))
+	sema.Release(5)
+}
@@ -8,7 +8,9 @@ import (
 	"time"
 )
 
-// Sleep implements sleeping with cancellation.
+// Sleep implements sleeping with cancellation. It returns true when the full
+// duration elapsed and false when ctx was canceled first. The timer is
+// stopped in both cases.
 func Sleep(ctx context.Context, duration time.Duration) bool {
 	timer := time.NewTimer(duration)
 	defer timer.Stop()
@@ -42,3 +42,23 @@ func TestSleep_Cancel(t *testing.T) {
 		t.Error("sleep took too long")
 	}
 }
+
+func TestSleep_CancelDuringSleep(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	if sync2.Sleep(ctx, 5*time.Second) {
+		t.Error("expected false as result")
+	}
+	if time.Since(start) > time.Second {
+		t.Error("sleep took too long")
+	}
+}
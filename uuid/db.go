@@ -57,6 +57,10 @@ func (n *NullUUID) Scan(value interface{}) error {
 		return nil
 	}
 
-	n.Valid = true
-	return n.UUID.Scan(value)
+	var x UUID
+	if err := x.Scan(value); err != nil {
+		return err
+	}
+	n.UUID, n.Valid = x, true
+	return nil
 }
@@ -41,9 +41,27 @@ func TestNullValuer(t *testing.T) {
 
 	err = b.Scan(nil)
 	require.NoError(t, err)
-	require.Equal(t, uuid.NullUUID{}, a)
+	require.Equal(t, uuid.NullUUID{}, b)
 
 	err = b.Scan([]byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8})
 	require.NoError(t, err)
 	require.Equal(t, expected, b)
 }
+
+func TestNullValuer_Value(t *testing.T) {
+	value, err := uuid.NullUUID{}.Value()
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	id := uuid.UUID{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	value, err = uuid.NullUUID{UUID: id, Valid: true}.Value()
+	require.NoError(t, err)
+	require.Equal(t, id[:], value)
+
+	// failed scans leave the value null
+	var n uuid.NullUUID
+	require.Error(t, n.Scan([]byte{1, 2, 3}))
+	require.Equal(t, uuid.NullUUID{}, n)
+	require.Error(t, n.Scan(42))
+	require.Equal(t, uuid.NullUUID{}, n)
+}
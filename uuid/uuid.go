@@ -34,10 +34,50 @@ func newRandomFromReader(r io.Reader) (UUID, error) {
 		return uuid, Error.Wrap(err)
 	}
 
-	// version 4, variant 2
+	uuid.setVersion4()
+	return uuid, nil
+}
+
+// NewBatch returns n random UUIDs (version 4 variant 2) generated from a
+// single read from crypto/rand.
+func NewBatch(n int) ([]UUID, error) {
+	return newBatchFromReader(rand.Reader, n)
+}
+
+// newBatchFromReader returns n random UUIDs (version 4 variant 2)
+// using a custom reader.
+func newBatchFromReader(r io.Reader, n int) ([]UUID, error) {
+	if n < 0 {
+		return nil, Error.New("invalid batch size %d", n)
+	}
+
+	data := make([]byte, n*len(UUID{}))
+	_, err := io.ReadFull(r, data)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	uuids := make([]UUID, n)
+	for i := range uuids {
+		copy(uuids[i][:], data[i*len(UUID{}):])
+		uuids[i].setVersion4()
+	}
+	return uuids, nil
+}
+
+// Must returns uuid and panics when err is not nil. It is intended for
+// wrapping calls such as New in tests and initialization code.
+func Must(uuid UUID, err error) UUID {
+	if err != nil {
+		panic(err)
+	}
+	return uuid
+}
+
+// setVersion4 sets the version 4 variant 2 bits.
+func (uuid *UUID) setVersion4() {
 	uuid[6] = (uuid[6] & 0x0f) | 0x40
 	uuid[8] = (uuid[8] & 0x3f) | 0x80
-	return uuid, nil
 }
 
 // IsZero returns true when all bytes in uuid are 0.
@@ -5,6 +5,7 @@ package uuid_test
 
 import (
 	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 
@@ -62,6 +63,37 @@ func TestRandom(t *testing.T) {
 	}
 }
 
+func TestNewBatch(t *testing.T) {
+	uuids, err := uuid.NewBatch(1000)
+	require.NoError(t, err)
+	require.Len(t, uuids, 1000)
+
+	seen := make(map[uuid.UUID]bool, len(uuids))
+	for _, x := range uuids {
+		require.False(t, seen[x], x)
+		seen[x] = true
+
+		assert.Equal(t, byte(0x40), x[6]&0xf0)
+		assert.Equal(t, byte(0x80), x[8]&0xc0)
+	}
+
+	uuids, err = uuid.NewBatch(0)
+	require.NoError(t, err)
+	require.Len(t, uuids, 0)
+
+	_, err = uuid.NewBatch(-1)
+	require.Error(t, err)
+}
+
+func TestMust(t *testing.T) {
+	x := uuid.Must(uuid.New())
+	require.False(t, x.IsZero())
+
+	require.Panics(t, func() {
+		uuid.Must(uuid.UUID{}, errors.New("failure"))
+	})
+}
+
 func TestJSON(t *testing.T) {
 	type example struct {
 		A uuid.UUID